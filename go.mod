@@ -4,6 +4,7 @@ go 1.23.0
 
 require (
 	github.com/charmbracelet/lipgloss v1.1.0
+	github.com/klauspost/compress v1.18.4
 	github.com/muesli/termenv v0.16.0
 	github.com/stretchr/testify v1.11.1
 	golang.org/x/term v0.32.0
@@ -1,11 +1,17 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/mfridman/tparse/internal/utils"
 	"github.com/mfridman/tparse/parse"
 )
 
@@ -20,12 +26,29 @@ type Options struct {
 	Sorter parse.PackageSorter
 	// ShowNoTests will display packages containing no test files or empty test files.
 	ShowNoTests bool
-	// FileName will read test output from a file.
+	// FileName will read test output from a file. Files compressed with gzip or zstd are
+	// decompressed transparently.
 	FileName string
+	// FileNames will read test output from multiple files, such as the output of sharded CI runs,
+	// and merge them into a single summary. Glob patterns are expanded. May be used together with
+	// FileName.
+	FileNames []string
 
 	// Test table options
-	TestTableOptions    TestTableOptions
-	SummaryTableOptions SummaryTableOptions
+	TestTableOptions      TestTableOptions
+	SummaryTableOptions   SummaryTableOptions
+	BenchmarkTableOptions BenchmarkTableOptions
+	CoverageTableOptions  CoverageTableOptions
+	TimelineTableOptions  TimelineTableOptions
+	FailedOptions         FailedOptions
+
+	// CoverProfile will read a coverage profile written by go test -coverprofile, and display the
+	// coverage by package, file and function.
+	CoverProfile string
+
+	// Timeline will display when each test ran and for how long it was paused waiting to run, and
+	// the peak concurrency of each package.
+	Timeline bool
 
 	// FollowOutput will follow the raw output as go test is running.
 	FollowOutput        bool           // Output to stdout
@@ -51,50 +74,189 @@ type Options struct {
 
 	// Used with FollowOutput, when enabled it would include timestamp with log lines
 	IncludeTimestamp bool
+
+	// MaxLineSize is the maximum size, in bytes, of a single line of go test output. Longer lines
+	// are truncated. Defaults to parse.DefaultMaxLineSize.
+	MaxLineSize int
 }
 
 func Run(option Options) (int, error) {
-	var reader io.ReadCloser
-	var err error
-	if option.FileName != "" {
-		if reader, err = os.Open(option.FileName); err != nil {
-			return 1, err
-		}
-	} else {
-		if reader, err = newPipeReader(); err != nil {
-			return 1, errors.New("stdin must be a pipe, or use -file to open a go test output file")
-		}
+	files, err := expandFiles(append([]string{option.FileName}, option.FileNames...))
+	if err != nil {
+		return 1, err
 	}
-	defer reader.Close()
-
 	if option.FollowOutputWriter != nil {
 		defer option.FollowOutputWriter.Close()
 	}
 
-	progressWriter := newConsoleWriter(option.Output, option.Format, option.DisableColor)
-	summary, err := parse.Process(
-		reader,
+	progressOutput := option.Output
+	if option.Format == OutputFormatJSON {
+		// Keep the output valid JSON, the report is the only thing written to it.
+		progressOutput = os.Stderr
+	}
+	progressWriter := newConsoleWriter(progressOutput, option.Format, option.DisableColor)
+	parseOptions := []parse.OptionsFunc{
 		parse.WithFollowOutput(option.FollowOutput),
 		parse.WithFollowVersboseOutput(option.FollowOutputVerbose),
 		parse.WithWriter(option.FollowOutputWriter),
 		parse.WithProgress(option.Progress),
 		parse.WithProgressOutput(progressWriter),
 		parse.WithIncludeTimestamp(option.IncludeTimestamp),
-	)
-	if err != nil {
-		return 1, err
+		parse.WithMaxLineSize(option.MaxLineSize),
+	}
+	var summary *parse.GoTestSummary
+	if len(files) > 0 {
+		if summary, err = processFiles(files, parseOptions); err != nil {
+			return 1, err
+		}
+	} else {
+		reader, err := newPipeReader()
+		if err != nil {
+			return 1, errors.New("stdin must be a pipe, or use -file to open a go test output file")
+		}
+		defer reader.Close()
+		if summary, err = parse.Process(reader, parseOptions...); err != nil {
+			return 1, err
+		}
 	}
 	if len(summary.Packages) == 0 {
 		return 1, fmt.Errorf("found no go test packages")
 	}
+	var profile *parse.CoverProfile
+	if option.CoverProfile != "" {
+		if profile, err = readCoverProfile(option.CoverProfile); err != nil {
+			return 1, err
+		}
+	}
+	// Build output that did not fail the build, such as linker warnings, is not part of the tables
+	// but should not be swallowed either.
+	for _, b := range sortedBuilds(summary) {
+		if !b.Failed {
+			fmt.Fprint(os.Stderr, b.Output())
+		}
+	}
 	// Useful for tests that don't need tparse table output. Very useful for testing output from
 	// [parse.Process]
 	if !option.DisableTableOutput {
-		display(option.Output, summary, option)
+		if option.Format == OutputFormatJSON {
+			if err := writeReport(option.Output, summary, profile); err != nil {
+				return 1, err
+			}
+		} else {
+			display(option.Output, summary, profile, option)
+		}
 	}
 	return summary.ExitCode(), nil
 }
 
+func sortedBuilds(summary *parse.GoTestSummary) []*parse.Build {
+	builds := make([]*parse.Build, 0, len(summary.Builds))
+	for _, b := range summary.Builds {
+		builds = append(builds, b)
+	}
+	sort.Slice(builds, func(i, j int) bool {
+		return builds[i].ImportPath < builds[j].ImportPath
+	})
+	return builds
+}
+
+// expandFiles expands glob patterns and removes empty and duplicate file names. It is an error for
+// a pattern to match no files.
+func expandFiles(patterns []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+	for _, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		matches := []string{pattern}
+		if strings.ContainsAny(pattern, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(pattern); err != nil {
+				return nil, fmt.Errorf("invalid file pattern %q: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %q", pattern)
+			}
+		}
+		for _, name := range matches {
+			if !seen[name] {
+				seen[name] = true
+				files = append(files, name)
+			}
+		}
+	}
+	return files, nil
+}
+
+// processFiles parses each file, and merges the results if there is more than one.
+func processFiles(files []string, parseOptions []parse.OptionsFunc) (*parse.GoTestSummary, error) {
+	summaries := make([]*parse.GoTestSummary, 0, len(files))
+	for _, name := range files {
+		f, err := utils.OpenFile(name)
+		if err != nil {
+			return nil, err
+		}
+		summary, err := parse.Process(f, parseOptions...)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		summaries = append(summaries, summary)
+	}
+	if len(summaries) == 1 {
+		return summaries[0], nil
+	}
+	return parse.Merge(summaries...), nil
+}
+
+// readCoverProfile reads a coverage profile, which may be compressed like the test output files.
+func readCoverProfile(name string) (*parse.CoverProfile, error) {
+	f, err := utils.OpenFile(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	profile, err := parse.ParseCoverProfile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return profile, nil
+}
+
+// writeReport writes the summary as indented JSON, see parse.Report. The coverage profile, if any,
+// is included the same way as the coverage tables, see coverageTables.
+func writeReport(w io.Writer, summary *parse.GoTestSummary, profile *parse.CoverProfile) error {
+	report := summary.Report()
+	if profile != nil {
+		root, modulePath := findModule()
+		report.CoverageProfile = parse.NewReportCoverageProfile(profile, func(name string) ([]byte, error) {
+			return readSource(root, modulePath, name)
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// readCompare reads the file to compare against, which is either go test output or a report
+// written with -format json.
+func readCompare(r io.Reader) (*parse.GoTestSummary, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	report, err := parse.ReadReport(bytes.NewReader(data))
+	if err == nil {
+		return report.Summary(), nil
+	}
+	var syntaxErr *json.SyntaxError
+	if !errors.Is(err, parse.ErrNotReport) && !errors.As(err, &syntaxErr) {
+		return nil, err
+	}
+	return parse.Process(bytes.NewReader(data))
+}
+
 func newPipeReader() (io.ReadCloser, error) {
 	finfo, err := os.Stdin.Stat()
 	if err != nil {
@@ -107,7 +269,7 @@ func newPipeReader() (io.ReadCloser, error) {
 	return nil, errors.New("stdin must be a pipe")
 }
 
-func display(w io.Writer, summary *parse.GoTestSummary, option Options) {
+func display(w io.Writer, summary *parse.GoTestSummary, profile *parse.CoverProfile, option Options) {
 	// Best effort to open the compare against file, if it exists.
 	var warnings []string
 	defer func() {
@@ -118,12 +280,12 @@ func display(w io.Writer, summary *parse.GoTestSummary, option Options) {
 	var against *parse.GoTestSummary
 	if option.Compare != "" {
 		// TODO(mf): cleanup, this is messy.
-		f, err := os.Open(option.Compare)
+		f, err := utils.OpenFile(option.Compare)
 		if err != nil {
 			warnings = append(warnings, fmt.Sprintf("failed to open against file: %s", option.Compare))
 		} else {
 			defer f.Close()
-			against, err = parse.Process(f)
+			against, err = readCompare(f)
 			if err != nil {
 				warnings = append(warnings, fmt.Sprintf("failed to parse against file: %s", option.Compare))
 			}
@@ -141,7 +303,21 @@ func display(w io.Writer, summary *parse.GoTestSummary, option Options) {
 			cw.testsTable(packages, option.TestTableOptions)
 		}
 	}
+	if option.TestTableOptions.GroupSkips {
+		cw.skipReasonsTable(packages, option.TestTableOptions)
+	}
+	if option.TestTableOptions.GroupAttr != "" {
+		cw.attrsTable(packages, option.TestTableOptions.GroupAttr, option.TestTableOptions)
+	}
+	// Benchmark results (if any) are always printed.
+	cw.benchmarksTable(packages, option.BenchmarkTableOptions)
+	if profile != nil {
+		cw.coverageTables(profile, option.CoverageTableOptions)
+	}
+	if option.Timeline {
+		cw.timelineTables(packages, option.TimelineTableOptions)
+	}
 	// Failures (if any) and summary table are always printed.
-	cw.printFailed(packages)
+	cw.printFailed(packages, option.FailedOptions)
 	cw.summaryTable(packages, option.ShowNoTests, option.SummaryTableOptions, against)
 }
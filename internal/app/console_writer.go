@@ -19,6 +19,8 @@ const (
 	OutputFormatBasic
 	// OutputFormatBasic is a markdown-rendered table
 	OutputFormatMarkdown
+	// OutputFormatJSON is a machine-readable summary instead of tables, see parse.Report
+	OutputFormatJSON
 )
 
 type consoleWriter struct {
@@ -28,14 +30,18 @@ type consoleWriter struct {
 	red    colorOptionFunc
 	green  colorOptionFunc
 	yellow colorOptionFunc
+
+	// renderer renders the styles of the writer. Unlike the default renderer, its color profile
+	// is not shared, so disabled colors stay disabled regardless of other writers.
+	renderer *lipgloss.Renderer
 }
 
 type colorOptionFunc func(s string) string
 
 // newColor is a helper function to set the base color.
-func newColor(color lipgloss.TerminalColor) colorOptionFunc {
+func newColor(r *lipgloss.Renderer, color lipgloss.TerminalColor) colorOptionFunc {
 	return func(text string) string {
-		return lipgloss.NewStyle().Foreground(color).Render(text)
+		return r.NewStyle().Foreground(color).Render(text)
 	}
 }
 
@@ -55,12 +61,14 @@ func newConsoleWriter(w io.Writer, format OutputFormat, disableColor bool) *cons
 		format = OutputFormatBasic
 	}
 	cw := &consoleWriter{
-		Writer: w,
-		format: format,
+		Writer:   w,
+		format:   format,
+		renderer: lipgloss.NewRenderer(w),
 	}
 	cw.red = noColor()
 	cw.green = noColor()
 	cw.yellow = noColor()
+	cw.renderer.SetColorProfile(termenv.Ascii)
 
 	if !disableColor {
 		// NOTE(mf): GitHub Actions CI env (and probably others) do not have an
@@ -69,7 +77,7 @@ func newConsoleWriter(w io.Writer, format OutputFormat, disableColor bool) *cons
 		// setting a color profile explicitly instead of relying on termenv to auto-detect.
 		// Ref: https://github.com/charmbracelet/lipgloss/issues/74
 		// Ref: https://github.com/mfridman/tparse/issues/76
-		lipgloss.SetColorProfile(termenv.TrueColor)
+		cw.renderer.SetColorProfile(termenv.TrueColor)
 
 		switch format {
 		case OutputFormatMarkdown:
@@ -77,20 +85,25 @@ func newConsoleWriter(w io.Writer, format OutputFormat, disableColor bool) *cons
 			cw.yellow = newMarkdownColor("🟡")
 			cw.red = newMarkdownColor("🔴")
 		default:
-			cw.green = newColor(lipgloss.Color("10"))
-			cw.yellow = newColor(lipgloss.Color("11"))
-			cw.red = newColor(lipgloss.Color("9"))
+			cw.green = newColor(cw.renderer, lipgloss.Color("10"))
+			cw.yellow = newColor(cw.renderer, lipgloss.Color("11"))
+			cw.red = newColor(cw.renderer, lipgloss.Color("9"))
 		}
 	}
 	return cw
 }
 
+// newStyle returns a style rendered by the writer, without colors if colors are disabled.
+func (w *consoleWriter) newStyle() lipgloss.Style {
+	return w.renderer.NewStyle()
+}
+
 func (w *consoleWriter) FormatAction(action parse.Action) string {
 	s := strings.ToUpper(action.String())
 	switch action {
 	case parse.ActionPass:
 		return w.green(s)
-	case parse.ActionSkip:
+	case parse.ActionSkip, parse.ActionIncomplete:
 		return w.yellow(s)
 	case parse.ActionFail:
 		return w.red(s)
@@ -5,12 +5,12 @@ import (
 	"github.com/charmbracelet/lipgloss/table"
 )
 
-func newTable(
-	format OutputFormat,
+// newTable returns a table in the output format of the writer, styled with its renderer.
+func (c *consoleWriter) newTable(
 	override func(style lipgloss.Style, row, col int) lipgloss.Style,
 ) *table.Table {
-	tbl := table.New()
-	switch format {
+	tbl := table.New().BorderStyle(c.newStyle())
+	switch c.format {
 	case OutputFormatPlain:
 		tbl.Border(lipgloss.HiddenBorder()).BorderTop(false).BorderBottom(false)
 	case OutputFormatMarkdown:
@@ -20,7 +20,7 @@ func newTable(
 	}
 	return tbl.StyleFunc(func(row, col int) lipgloss.Style {
 		// Default style, may be overridden.
-		style := lipgloss.NewStyle().PaddingLeft(1).PaddingRight(1).Align(lipgloss.Center)
+		style := c.newStyle().PaddingLeft(1).PaddingRight(1).Align(lipgloss.Center)
 		if override != nil {
 			style = override(style, row, col)
 		}
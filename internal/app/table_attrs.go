@@ -0,0 +1,137 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+
+	"github.com/mfridman/tparse/parse"
+)
+
+// attrsTable prints the number of tests by status for each value of the attribute with the given
+// key, e.g., for each owner with -group-attr owner. Tests without the attribute are counted last.
+// Nothing is printed if no test has the attribute.
+func (c *consoleWriter) attrsTable(packages []*parse.Package, key string, option TestTableOptions) {
+	type group struct {
+		pass, fail, skip, incomplete, total int
+	}
+	groups := make(map[string]*group)
+	var found, showIncomplete bool
+	for _, pkg := range packages {
+		// Discard packages where we cannot generate a sensible test summary.
+		if pkg.NoTestFiles || pkg.NoTests || pkg.HasPanic {
+			continue
+		}
+		for _, t := range pkg.Tests {
+			if !matchAttrs(t, option.Attrs) {
+				continue
+			}
+			value, ok := t.Attr(key)
+			if ok {
+				found = true
+			} else {
+				value = "--"
+			}
+			g := groups[value]
+			if g == nil {
+				g = &group{}
+				groups[value] = g
+			}
+			g.total++
+			switch t.Status() {
+			case parse.ActionPass:
+				g.pass++
+			case parse.ActionFail:
+				g.fail++
+			case parse.ActionSkip:
+				g.skip++
+			case parse.ActionIncomplete:
+				g.incomplete++
+				showIncomplete = true
+			}
+		}
+	}
+	if !found {
+		return
+	}
+	values := make([]string, 0, len(groups))
+	for value := range groups {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if (values[i] == "--") != (values[j] == "--") {
+			return values[j] == "--"
+		}
+		return values[i] < values[j]
+	})
+
+	tbl := c.newTable(func(style lipgloss.Style, row, col int) lipgloss.Style {
+		switch row {
+		case table.HeaderRow:
+		default:
+			if col == 0 {
+				// Attribute value
+				style = style.Align(lipgloss.Left)
+			}
+		}
+		return style
+	})
+	// Same as the summary table, the incomplete column is only shown if there are incomplete tests.
+	header := []string{key, "Tests", "Pass", "Fail", "Skip"}
+	if showIncomplete {
+		header = append(header, "Incomplete")
+	}
+	tbl.Headers(header...)
+	data := table.NewStringData()
+	for _, value := range values {
+		g := groups[value]
+		fail := strconv.Itoa(g.fail)
+		if g.fail > 0 {
+			fail = c.red(fail)
+		}
+		row := []string{
+			value,
+			strconv.Itoa(g.total),
+			strconv.Itoa(g.pass),
+			fail,
+			strconv.Itoa(g.skip),
+		}
+		if showIncomplete {
+			row = append(row, strconv.Itoa(g.incomplete))
+		}
+		data.Append(row)
+	}
+	fmt.Fprintln(c, tbl.Data(data).Render())
+	if c.format == OutputFormatMarkdown {
+		// Separate the markdown table from whatever follows, otherwise the tables are merged.
+		fmt.Fprintln(c)
+	}
+}
+
+// matchAttrs reports whether the test has all the given attributes, including attributes inherited
+// from parent tests, see parse.Test.Attr.
+func matchAttrs(t *parse.Test, attrs map[string]string) bool {
+	for key, want := range attrs {
+		if got, ok := t.Attr(key); !ok || got != want {
+			return false
+		}
+	}
+	return true
+}
+
+// filterAttrs returns the tests that have all the given attributes, see matchAttrs.
+func filterAttrs(tests []*parse.Test, attrs map[string]string) []*parse.Test {
+	if len(attrs) == 0 {
+		return tests
+	}
+	var filtered []*parse.Test
+	for _, t := range tests {
+		if matchAttrs(t, attrs) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+
+	"github.com/mfridman/tparse/internal/utils"
+	"github.com/mfridman/tparse/parse"
+)
+
+type BenchmarkTableOptions struct {
+	// For narrow screens, trim long benchmark identifiers vertically. See TestTableOptions.Trim.
+	Trim bool
+
+	// TrimPath is the path prefix to trim from the package name.
+	TrimPath string
+}
+
+// benchmarksTable prints all benchmark results, grouped by alphabetically sorted packages and in
+// the order they were reported within a package, followed by the benchmarks of the package that
+// failed without a result. Nothing is printed if there are no benchmarks.
+func (c *consoleWriter) benchmarksTable(packages []*parse.Package, option BenchmarkTableOptions) {
+	tbl := c.newTable(func(style lipgloss.Style, row, col int) lipgloss.Style {
+		switch row {
+		case table.HeaderRow:
+		default:
+			switch col {
+			case 0, 5, 6:
+				// Benchmark name, metrics and package name
+				style = style.Align(lipgloss.Left)
+			case 1, 2, 3, 4:
+				style = style.Align(lipgloss.Right)
+			}
+		}
+		return style
+	})
+	header := benchmarkRow{
+		name:        "Benchmark",
+		iterations:  "Iterations",
+		nsPerOp:     "ns/op",
+		bytesPerOp:  "B/op",
+		allocsPerOp: "allocs/op",
+		metrics:     "Metrics",
+		packageName: "Package",
+	}
+	tbl.Headers(header.toRow()...)
+	data := table.NewStringData()
+
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Summary.Package)
+	}
+	packagePrefix := utils.FindLongestCommonPrefix(names)
+
+	var n int
+	for _, pkg := range packages {
+		var failed []*parse.Test
+		for _, t := range pkg.TestsByAction(parse.ActionFail) {
+			if t.IsBenchmark() {
+				failed = append(failed, t)
+			}
+		}
+		if len(pkg.Benchmarks) == 0 && len(failed) == 0 {
+			continue
+		}
+		if n > 0 {
+			// Add a blank row between packages.
+			data.Append(benchmarkRow{}.toRow())
+		}
+		n++
+		for _, b := range pkg.Benchmarks {
+			row := benchmarkRow{
+				name:        shortenTestName(b.FullName(), option.Trim, 32),
+				iterations:  strconv.FormatInt(b.Iterations, 10),
+				nsPerOp:     formatBenchmarkValue(b.NsPerOp),
+				bytesPerOp:  "--",
+				allocsPerOp: "--",
+				metrics:     formatBenchmarkMetrics(b),
+				packageName: shortenPackageName(b.Package, packagePrefix, 16, option.Trim, option.TrimPath),
+			}
+			if b.HasMem {
+				row.bytesPerOp = strconv.FormatFloat(b.BytesPerOp, 'f', -1, 64)
+				row.allocsPerOp = strconv.FormatFloat(b.AllocsPerOp, 'f', -1, 64)
+			}
+			data.Append(row.toRow())
+		}
+		for _, t := range failed {
+			row := benchmarkRow{
+				name:        shortenTestName(t.Name, option.Trim, 32),
+				iterations:  c.red("FAIL"),
+				nsPerOp:     "--",
+				bytesPerOp:  "--",
+				allocsPerOp: "--",
+				metrics:     "--",
+				packageName: shortenPackageName(pkg.Summary.Package, packagePrefix, 16, option.Trim, option.TrimPath),
+			}
+			data.Append(row.toRow())
+		}
+	}
+	if data.Rows() == 0 {
+		return
+	}
+	fmt.Fprintln(c, tbl.Data(data).Render())
+	if c.format == OutputFormatMarkdown {
+		// Separate the markdown table from whatever follows, otherwise the tables are merged.
+		fmt.Fprintln(c)
+	}
+}
+
+// formatBenchmarkMetrics returns the MB/s and custom metrics of a benchmark, sorted by unit, or
+// "--" if there are none.
+func formatBenchmarkMetrics(b *parse.Benchmark) string {
+	var metrics []string
+	if b.MBPerSec > 0 {
+		metrics = append(metrics, formatBenchmarkValue(b.MBPerSec)+" MB/s")
+	}
+	units := make([]string, 0, len(b.Metrics))
+	for unit := range b.Metrics {
+		units = append(units, unit)
+	}
+	sort.Strings(units)
+	for _, unit := range units {
+		metrics = append(metrics, formatBenchmarkValue(b.Metrics[unit])+" "+unit)
+	}
+	if len(metrics) == 0 {
+		return "--"
+	}
+	return strings.Join(metrics, ", ")
+}
+
+// formatBenchmarkValue formats a benchmark value with the same precision go test uses, which
+// depends on the magnitude of the value.
+func formatBenchmarkValue(v float64) string {
+	var prec int
+	switch y := math.Abs(v); {
+	case y == 0 || y >= 999.95:
+		prec = 0
+	case y >= 99.995:
+		prec = 1
+	case y >= 9.9995:
+		prec = 2
+	case y >= 0.99995:
+		prec = 3
+	case y >= 0.099995:
+		prec = 4
+	case y >= 0.0099995:
+		prec = 5
+	default:
+		prec = 6
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
+
+type benchmarkRow struct {
+	name        string
+	iterations  string
+	nsPerOp     string
+	bytesPerOp  string
+	allocsPerOp string
+	metrics     string
+	packageName string
+}
+
+func (r benchmarkRow) toRow() []string {
+	return []string{
+		r.name,
+		r.iterations,
+		r.nsPerOp,
+		r.bytesPerOp,
+		r.allocsPerOp,
+		r.metrics,
+		r.packageName,
+	}
+}
@@ -0,0 +1,208 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+
+	"github.com/mfridman/tparse/internal/utils"
+	"github.com/mfridman/tparse/parse"
+)
+
+type CoverageTableOptions struct {
+	// For narrow screens, trim long package and file names vertically. See
+	// SummaryTableOptions.Trim.
+	Trim bool
+
+	// TrimPath is the path prefix to trim from package and file names.
+	TrimPath string
+}
+
+// coverageTables prints the coverage of a coverage profile by package, file and function, the
+// least covered first. The package table ends with the total of all packages.
+//
+// Functions are found by parsing the source files, which are looked up in the module of the
+// current directory. Files outside of the module are left out of the function table.
+func (c *consoleWriter) coverageTables(profile *parse.CoverProfile, option CoverageTableOptions) {
+	if len(profile.Files) == 0 {
+		return
+	}
+	packages := profile.Packages()
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	packagePrefix := utils.FindLongestCommonPrefix(names)
+	shorten := func(name string) string {
+		return shortenPackageName(name, packagePrefix, 32, option.Trim, option.TrimPath)
+	}
+
+	var rows []coverageRow
+	for _, name := range names {
+		rows = append(rows, coverageRow{name: shorten(name), cover: packages[name]})
+	}
+	sortCoverageRows(rows)
+	rows = append(rows, coverageRow{name: "Total", cover: profile.Total()})
+	c.coverageTable("Package", rows)
+
+	rows = rows[:0]
+	for _, f := range profile.Files {
+		rows = append(rows, coverageRow{name: shorten(f.Name), cover: f.Coverage()})
+	}
+	sortCoverageRows(rows)
+	c.coverageTable("File", rows)
+
+	rows = rows[:0]
+	root, modulePath := findModule()
+	for _, f := range profile.Files {
+		src, err := readSource(root, modulePath, f.Name)
+		if err != nil {
+			continue
+		}
+		funcs, err := f.Functions(src)
+		if err != nil {
+			continue
+		}
+		for _, fn := range funcs {
+			rows = append(rows, coverageRow{
+				name:     fn.Name,
+				location: shorten(f.Name) + ":" + strconv.Itoa(fn.Line),
+				cover:    fn.Coverage,
+			})
+		}
+	}
+	sortCoverageRows(rows)
+	c.coverageTable("Function", rows)
+}
+
+// coverageTable prints a coverage table, named by the given column. Nothing is printed if there
+// are no rows.
+func (c *consoleWriter) coverageTable(column string, rows []coverageRow) {
+	if len(rows) == 0 {
+		return
+	}
+	var withLocation bool
+	for _, r := range rows {
+		withLocation = withLocation || r.location != ""
+	}
+	tbl := c.newTable(func(style lipgloss.Style, row, col int) lipgloss.Style {
+		switch row {
+		case table.HeaderRow:
+		default:
+			if col >= 2 {
+				// Name and location
+				style = style.Align(lipgloss.Left)
+			}
+		}
+		return style
+	})
+	header := []string{"Cover", "Statements", column}
+	if withLocation {
+		header = append(header, "Location")
+	}
+	tbl.Headers(header...)
+	data := table.NewStringData()
+	for _, r := range rows {
+		row := []string{
+			c.formatCoverage(r.cover),
+			strconv.Itoa(r.cover.Covered) + "/" + strconv.Itoa(r.cover.Statements),
+			r.name,
+		}
+		if withLocation {
+			row = append(row, r.location)
+		}
+		data.Append(row)
+	}
+	fmt.Fprintln(c, tbl.Data(data).Render())
+	if c.format == OutputFormatMarkdown {
+		// Separate the markdown table from whatever follows, otherwise the tables are merged.
+		fmt.Fprintln(c)
+	}
+}
+
+// formatCoverage returns the coverage as a percentage, colorized like the summary table. Markdown
+// output is not colorized.
+func (c *consoleWriter) formatCoverage(cover parse.Coverage) string {
+	if cover.Statements == 0 {
+		return "--"
+	}
+	s := fmt.Sprintf("%.1f%%", cover.Percent())
+	if c.format == OutputFormatMarkdown {
+		return s
+	}
+	switch p := cover.Percent(); {
+	case p <= 50.0:
+		return c.red(s)
+	case p < 80.0:
+		return c.yellow(s)
+	default:
+		return c.green(s)
+	}
+}
+
+type coverageRow struct {
+	name     string
+	location string
+	cover    parse.Coverage
+}
+
+// sortCoverageRows sorts the rows by coverage ASC, the least covered first, then by name.
+// Rows without statements have nothing to cover and are sorted last.
+func sortCoverageRows(rows []coverageRow) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].cover, rows[j].cover
+		if (a.Statements == 0) != (b.Statements == 0) {
+			return b.Statements == 0
+		}
+		if a.Percent() != b.Percent() {
+			return a.Percent() < b.Percent()
+		}
+		if rows[i].name != rows[j].name {
+			return rows[i].name < rows[j].name
+		}
+		return rows[i].location < rows[j].location
+	})
+}
+
+// findModule returns the root directory and the path of the module that contains the current
+// directory, or empty strings if there is none.
+func findModule() (root, modulePath string) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", ""
+	}
+	for {
+		if f, err := os.Open(filepath.Join(dir, "go.mod")); err == nil {
+			defer f.Close()
+			sc := bufio.NewScanner(f)
+			for sc.Scan() {
+				if name, ok := strings.CutPrefix(strings.TrimSpace(sc.Text()), "module "); ok {
+					return dir, strings.Trim(strings.TrimSpace(name), `"`)
+				}
+			}
+			return "", ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
+
+// readSource reads a file of a coverage profile, e.g., "example.com/foo/foo.go", from the module
+// at root.
+func readSource(root, modulePath, name string) ([]byte, error) {
+	rel, ok := strings.CutPrefix(name, modulePath+"/")
+	if root == "" || !ok {
+		return nil, fmt.Errorf("%s: not in module %q", name, modulePath)
+	}
+	return os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+}
@@ -3,7 +3,9 @@ package app
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -16,9 +18,22 @@ const (
 	defaultWidth = 96
 )
 
+// FailedOptions controls how failed tests are printed.
+type FailedOptions struct {
+	// Tree orders failed subtests under their parent test and indents them by depth. Parents that
+	// failed only because a subtest failed are rendered faint, so leaf failures stand out.
+	Tree bool
+	// RawPanic prints the full goroutine dump of a panic or timeout as-is, instead of a condensed
+	// trace.
+	RawPanic bool
+	// Attrs limits the failed tests to those with all of the given attributes, see
+	// TestTableOptions.Attrs.
+	Attrs map[string]string
+}
+
 // printFailed prints all failed tests, grouping them by package. Packages are sorted.
 // Panic is an exception.
-func (c *consoleWriter) printFailed(packages []*parse.Package) {
+func (c *consoleWriter) printFailed(packages []*parse.Package, option FailedOptions) {
 	width, _, err := term.GetSize(int(os.Stdout.Fd()))
 	if err != nil {
 		width = defaultWidth
@@ -28,68 +43,156 @@ func (c *consoleWriter) printFailed(packages []*parse.Package) {
 		if pkg.HasPanic {
 			// TODO(mf): document why panics are handled separately. A panic may or may
 			// not be associated with tests, so we print it at the package level.
-			output := c.prepareStyledPanic(pkg.Summary.Package, pkg.Summary.Test, pkg.PanicEvents, width)
+			output := c.prepareStyledPanic(pkg, option.RawPanic, width)
 			fmt.Fprintln(c, output)
 			continue
 		}
+		if pkg.FailedBuild != nil {
+			fmt.Fprintln(c, c.prepareStyledBuild(pkg))
+			continue
+		}
 		failedTests := pkg.TestsByAction(parse.ActionFail)
-		if len(failedTests) == 0 {
+		if pkg.Timeout == nil {
+			// Tests that never finished are printed along with the failed tests. If the package
+			// timed out they were still running, and are listed with the timeout instead.
+			failedTests = append(failedTests, pkg.TestsByAction(parse.ActionIncomplete)...)
+		}
+		// A package may fail without a test to blame, e.g., if TestMain or an init function exited
+		// with a non-zero code. The output of the package is all there is to explain it.
+		unexplained := pkg.Summary.Action == parse.ActionFail &&
+			len(failedTests) == 0 && len(pkg.DataRaces) == 0 && pkg.Timeout == nil
+		failedTests = filterAttrs(failedTests, option.Attrs)
+		if len(failedTests) == 0 && len(pkg.DataRaces) == 0 && pkg.Timeout == nil && !unexplained {
 			continue
 		}
-		styledPackageHeader := c.styledHeader(
-			pkg.Summary.Action.String(),
-			pkg.Summary.Package,
-		)
+		status := pkg.Summary.Action.String()
+		if status == "" {
+			status = parse.ActionIncomplete.String()
+		}
+		if pkg.Timeout != nil {
+			status = "timeout"
+		}
+		styledPackageHeader := c.styledHeader(status, pkg.Summary.Package+shuffleHeader(pkg))
 		fmt.Fprintln(c, styledPackageHeader)
 		fmt.Fprintln(c)
-		/*
-			Failed tests are all the individual tests, where the subtests are not separated.
-
-			We need to sort the tests by name to ensure they are grouped together
-		*/
-		sort.Slice(failedTests, func(i, j int) bool {
-			return failedTests[i].Name < failedTests[j].Name
-		})
-
-		divider := lipgloss.NewStyle().
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderTop(true).
-			Faint(c.format != OutputFormatMarkdown).
-			Width(width)
-
-		/*
-			Note, some output such as the "--- FAIL: " line is prefixed
-			with spaces. Unfortunately when dumping this in markdown format
-			it renders as an code block.
-
-			"To produce a code block in Markdown, simply indent every line of the
-			block by at least 4 spaces or 1 tab."
-			Ref. https://daringfireball.net/projects/markdown/syntax
-
-			Example:
-			 --- FAIL: Test (0.05s)
-			    --- FAIL: Test/test_01 (0.01s)
-			        --- FAIL: Test/test_01/sort (0.00s)
-
-			This is why we wrap the entire test output in a code block.
-		*/
-
-		if c.format == OutputFormatMarkdown {
-			fmt.Fprintln(c, fencedCodeBlock)
-		}
-		var key string
-		for i, t := range failedTests {
-			// Add top divider to all tests except first one.
-			base, _, _ := cut(t.Name, "/")
-			if i > 0 && key != base {
-				fmt.Fprintln(c, divider.String())
-			}
-			key = base
-			fmt.Fprintln(c, c.prepareStyledTest(t))
+		if pkg.Timeout != nil {
+			fmt.Fprintln(c, c.prepareStyledTimeout(pkg.Timeout, option.RawPanic))
 		}
-		if c.format == OutputFormatMarkdown {
-			fmt.Fprint(c, fencedCodeBlock+"\n\n")
+		if len(failedTests) > 0 {
+			c.printFailedTests(pkg, failedTests, option, width)
+		}
+		if len(pkg.DataRaces) > 0 {
+			fmt.Fprintln(c, c.prepareStyledDataRaces(pkg.DataRaces))
+		}
+		if unexplained {
+			fmt.Fprintln(c, c.prepareStyledPackageOutput(pkg))
+		}
+		if pkg.Shuffled {
+			fmt.Fprintln(c, c.prepareStyledShuffle(pkg))
+		}
+	}
+}
+
+// shuffleHeader returns the seed the tests of the package were shuffled with, to be added to the
+// package name in the header, or an empty string if they were not shuffled.
+func shuffleHeader(pkg *parse.Package) string {
+	if !pkg.Shuffled {
+		return ""
+	}
+	return " • -shuffle=" + strconv.FormatInt(pkg.ShuffleSeed, 10)
+}
+
+// prepareStyledShuffle returns the command that runs the tests of a package in the same order
+// again, see parse.Package.ShuffleCommand.
+func (c *consoleWriter) prepareStyledShuffle(pkg *parse.Package) string {
+	block := "To re-run in the same order: " + pkg.ShuffleCommand()
+	if c.format == OutputFormatMarkdown {
+		return fencedCodeBlock + "\n" + block + "\n" + fencedCodeBlock + "\n"
+	}
+	return c.newStyle().Foreground(lipgloss.Color("11")).Render(block) + "\n"
+}
+
+// prepareStyledPackageOutput returns the output of a package that is not attributed to a test,
+// for packages that failed without a failed test.
+func (c *consoleWriter) prepareStyledPackageOutput(pkg *parse.Package) string {
+	var rows strings.Builder
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	title := "No test failed, package output:"
+	if len(pkg.Output) == 0 {
+		title = "No test failed, and the package printed no output."
+	}
+	if c.format != OutputFormatMarkdown {
+		title = c.newStyle().Bold(true).Render(title)
+	}
+	rows.WriteString(title + "\n")
+	for _, e := range pkg.Output {
+		rows.WriteString("  " + e.Output)
+	}
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	return rows.String()
+}
+
+// printFailedTests prints the output of the failed tests of a package.
+func (c *consoleWriter) printFailedTests(
+	pkg *parse.Package,
+	failedTests []*parse.Test,
+	option FailedOptions,
+	width int,
+) {
+	/*
+		Failed tests are all the individual tests, where the subtests are not separated.
+
+		We need to sort the tests by name to ensure they are grouped together
+	*/
+	sort.Slice(failedTests, func(i, j int) bool {
+		return failedTests[i].Name < failedTests[j].Name
+	})
+	if option.Tree {
+		testNames(pkg, failedTests, true)
+	}
+
+	divider := c.newStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderTop(true).
+		Faint(c.format != OutputFormatMarkdown).
+		Width(width)
+
+	/*
+		Note, some output such as the "--- FAIL: " line is prefixed
+		with spaces. Unfortunately when dumping this in markdown format
+		it renders as an code block.
+
+		"To produce a code block in Markdown, simply indent every line of the
+		block by at least 4 spaces or 1 tab."
+		Ref. https://daringfireball.net/projects/markdown/syntax
+
+		Example:
+		 --- FAIL: Test (0.05s)
+		    --- FAIL: Test/test_01 (0.01s)
+		        --- FAIL: Test/test_01/sort (0.00s)
+
+		This is why we wrap the entire test output in a code block.
+	*/
+
+	if c.format == OutputFormatMarkdown {
+		fmt.Fprintln(c, fencedCodeBlock)
+	}
+	var key string
+	for i, t := range failedTests {
+		// Add top divider to all tests except first one.
+		base, _, _ := cut(t.Name, "/")
+		if i > 0 && key != base {
+			fmt.Fprintln(c, divider.String())
 		}
+		key = base
+		fmt.Fprintln(c, c.prepareStyledTest(t, option.Tree))
+	}
+	if c.format == OutputFormatMarkdown {
+		fmt.Fprint(c, fencedCodeBlock+"\n\n")
 	}
 }
 
@@ -106,30 +209,230 @@ func cut(s, sep string) (before, after string, found bool) {
 	return s, "", false
 }
 
-func (c *consoleWriter) prepareStyledPanic(
-	packageName string,
-	testName string,
-	panicEvents []*parse.Event,
-	width int,
-) string {
-	if testName != "" {
-		packageName = packageName + " • " + testName
+// prepareStyledPanic returns the panic of a package. By default the goroutine dump is condensed:
+// the frames of the panicking goroutine are listed one per line without the runtime frames that
+// handle the panic, frames in test files are highlighted, and each other goroutine is summarized
+// on a single line. If raw is true, the output is printed as-is.
+func (c *consoleWriter) prepareStyledPanic(pkg *parse.Package, raw bool, width int) string {
+	packageName := pkg.Summary.Package
+	if pkg.Summary.Test != "" {
+		packageName = packageName + " • " + pkg.Summary.Test
 	}
+	packageName += shuffleHeader(pkg)
 	styledPackageHeader := c.styledHeader("PANIC", packageName)
-	// TODO(mf): can we pass this panic stack to another package and either by default,
-	// or optionally, build human-readable panic output with:
-	// https://github.com/maruel/panicparse
 	var rows strings.Builder
-	for _, e := range panicEvents {
-		if e.Output == "" {
-			continue
+	if p := pkg.Panic(); raw || p.Goroutine == nil {
+		for _, e := range pkg.PanicEvents {
+			if e.Output == "" {
+				continue
+			}
+			rows.WriteString(e.Output)
 		}
-		rows.WriteString(e.Output)
+	} else {
+		rows.WriteString(c.prepareStyledGoroutines(p))
 	}
-	content := lipgloss.NewStyle().Width(width).Render(rows.String())
+	content := c.newStyle().Width(width).Render(rows.String())
 	return lipgloss.JoinVertical(lipgloss.Left, styledPackageHeader, content)
 }
 
+func (c *consoleWriter) prepareStyledGoroutines(p *parse.Panic) string {
+	var rows strings.Builder
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	rows.WriteString("panic: " + p.Message + "\n")
+	if p.Signal != "" {
+		rows.WriteString(p.Signal + "\n")
+	}
+	g := p.Goroutine
+	fmt.Fprintf(&rows, "\ngoroutine %d [%s]:\n", g.IDs[0], g.State)
+	frames := g.Frames
+	// Frames up to and including the call to panic belong to the runtime and the testing package,
+	// which recover and re-panic to report the failed test.
+	for i := len(frames) - 1; i >= 0; i-- {
+		if frames[i].Func == "panic" {
+			frames = frames[i+1:]
+			break
+		}
+	}
+	var calls []call
+	for _, f := range frames {
+		calls = append(calls, call{f.Func, f})
+	}
+	if g.CreatedBy != nil {
+		calls = append(calls, call{"created by " + g.CreatedBy.Func, g.CreatedBy})
+	}
+	rows.WriteString(c.prepareStyledCalls(calls, "  "))
+	if len(p.Others) > 0 {
+		rows.WriteString("\nother goroutines:\n")
+	}
+	for _, g := range p.Others {
+		name := fmt.Sprintf("goroutine %d", g.IDs[0])
+		if len(g.IDs) > 1 {
+			name = fmt.Sprintf("%d goroutines", len(g.IDs))
+		}
+		line := fmt.Sprintf("  %s [%s]", name, g.State)
+		if f := summaryFrame(g); f != nil {
+			line += ": " + f.Func + "  " + frameLocation(f)
+		}
+		rows.WriteString(line + "\n")
+	}
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	return rows.String()
+}
+
+// prepareStyledTimeout returns the tests that were still running when the test binary timed out,
+// and how long they had been running. The goroutine dump that follows is only included if raw is
+// true.
+func (c *consoleWriter) prepareStyledTimeout(timeout *parse.Timeout, raw bool) string {
+	var rows strings.Builder
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	if raw {
+		for _, e := range timeout.Events {
+			rows.WriteString(e.Output)
+		}
+	} else {
+		fmt.Fprintf(&rows, "test timed out after %s, running tests:\n", timeout.After)
+		var nameWidth int
+		for _, t := range timeout.Running {
+			nameWidth = max(nameWidth, len(t.Name))
+		}
+		for _, t := range timeout.Running {
+			line := "  " + c.red(t.Name)
+			if t.Elapsed > 0 {
+				line += strings.Repeat(" ", nameWidth-len(t.Name)) + "  (" + t.Elapsed.String() + ")"
+			}
+			rows.WriteString(line + "\n")
+		}
+	}
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	return rows.String()
+}
+
+// prepareStyledDataRaces returns the data races of a package, each with the tests that triggered
+// it and the stacks of the conflicting accesses. Frames of the testing package are left out, they
+// are the same for every test.
+func (c *consoleWriter) prepareStyledDataRaces(races []*parse.DataRace) string {
+	var rows strings.Builder
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	title := "Data races"
+	if c.format != OutputFormatMarkdown {
+		title = c.newStyle().Bold(true).Render(title)
+	}
+	rows.WriteString(title + "\n")
+	for i, r := range races {
+		tests := "outside of a test"
+		if len(r.Tests) > 0 {
+			tests = "in " + strings.Join(r.Tests, ", ")
+		}
+		rows.WriteString(c.red(fmt.Sprintf("\nRace %d %s", i+1, tests)) + "\n")
+		for _, a := range []*parse.RaceAccess{r.Access, r.Previous} {
+			by := "main goroutine"
+			if a.Goroutine != 0 {
+				by = fmt.Sprintf("goroutine %d", a.Goroutine)
+			}
+			op := a.Op
+			if a == r.Previous {
+				op = "Previous " + strings.ToLower(op)
+			}
+			fmt.Fprintf(&rows, "  %s at %s by %s:\n", op, a.Address, by)
+			rows.WriteString(c.prepareStyledCalls(raceCalls(a.Frames), "    "))
+			if calls := raceCalls(a.CreatedAt); len(calls) > 0 {
+				fmt.Fprintf(&rows, "  %s created at:\n", strings.ToUpper(by[:1])+by[1:])
+				rows.WriteString(c.prepareStyledCalls(calls, "    "))
+			}
+		}
+	}
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	return rows.String()
+}
+
+func raceCalls(frames []*parse.Frame) []call {
+	var calls []call
+	for _, f := range frames {
+		if strings.HasPrefix(f.Func, "testing.") || f.Func == "main.main" {
+			continue
+		}
+		calls = append(calls, call{f.Func, f})
+	}
+	return calls
+}
+
+// call is a row in a condensed stack trace.
+type call struct {
+	name  string
+	frame *parse.Frame
+}
+
+// prepareStyledCalls returns a condensed stack trace, one call per line with the function names
+// aligned, followed by the file name and line of the call. Calls in test files are highlighted.
+func (c *consoleWriter) prepareStyledCalls(calls []call, indent string) string {
+	var nameWidth int
+	for _, r := range calls {
+		nameWidth = max(nameWidth, len(r.name))
+	}
+	var rows strings.Builder
+	for _, r := range calls {
+		line := fmt.Sprintf("%s%-*s  %s", indent, nameWidth, r.name, frameLocation(r.frame))
+		if r.frame.IsTest() && c.format != OutputFormatMarkdown {
+			line = c.yellow(line)
+		}
+		rows.WriteString(line + "\n")
+	}
+	return rows.String()
+}
+
+// summaryFrame returns the frame that best describes what a goroutine is doing: the most recent
+// call in a test file, or the most recent call if there is none.
+func summaryFrame(g *parse.Goroutine) *parse.Frame {
+	for _, f := range g.Frames {
+		if f.IsTest() {
+			return f
+		}
+	}
+	if len(g.Frames) > 0 {
+		return g.Frames[0]
+	}
+	return g.CreatedBy
+}
+
+func frameLocation(f *parse.Frame) string {
+	return filepath.Base(f.File) + ":" + strconv.Itoa(f.Line)
+}
+
+// prepareStyledBuild returns the build output, such as compiler errors, of a package that failed
+// to build. The failed build may belong to another package, e.g., a dependency, in which case it
+// is named in the output.
+func (c *consoleWriter) prepareStyledBuild(pkg *parse.Package) string {
+	styledPackageHeader := c.styledHeader("BUILD FAILED", pkg.Summary.Package)
+
+	var rows strings.Builder
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	if pkg.FailedBuild.ImportPath != "" && !strings.HasPrefix(pkg.FailedBuild.ImportPath, pkg.Summary.Package+" ") &&
+		pkg.FailedBuild.ImportPath != pkg.Summary.Package {
+		rows.WriteString("caused by: " + pkg.FailedBuild.ImportPath + "\n\n")
+	}
+	for _, line := range pkg.FailedBuild.Errors() {
+		rows.WriteString(line + "\n")
+	}
+	if c.format == OutputFormatMarkdown {
+		rows.WriteString(fencedCodeBlock + "\n")
+	}
+	return styledPackageHeader + "\n\n" + rows.String()
+}
+
 func (c *consoleWriter) styledHeader(status, packageName string) string {
 	status = c.red(strings.ToUpper(status))
 	packageName = strings.TrimSpace(packageName)
@@ -152,14 +455,14 @@ func (c *consoleWriter) styledHeader(status, packageName string) string {
 
 		See https://github.com/mfridman/tparse/issues/71
 	*/
-	headerStyle := lipgloss.NewStyle().
+	headerStyle := c.newStyle().
 		BorderStyle(lipgloss.ThickBorder()).
 		BorderForeground(lipgloss.Color("103"))
-	statusStyle := lipgloss.NewStyle().
+	statusStyle := c.newStyle().
 		PaddingLeft(3).
 		PaddingRight(2).
 		Foreground(lipgloss.Color("9"))
-	packageNameStyle := lipgloss.NewStyle().
+	packageNameStyle := c.newStyle().
 		PaddingRight(3)
 	headerRow := lipgloss.JoinHorizontal(
 		lipgloss.Left,
@@ -173,18 +476,136 @@ const (
 	failLine = "--- FAIL: "
 )
 
-func (c *consoleWriter) prepareStyledTest(t *parse.Test) string {
-	t.SortEvents()
+func (c *consoleWriter) prepareStyledTest(t *parse.Test, tree bool) string {
+	var indent string
+	var propagated bool
+	if tree {
+		indent = strings.Repeat("  ", t.Depth())
+		propagated = t.IsPropagatedFailure()
+	}
+
+	var out string
+	attempts := t.Attempts()
+	if len(attempts) > 1 {
+		// The test ran more than once, e.g., with -count=N. Only the output of failed attempts is
+		// relevant, preceded by how many attempts passed. Attempts that failed the same way are
+		// printed once.
+		out = indent + attemptSummary(t)
+		if c.format != OutputFormatMarkdown {
+			out = c.newStyle().Foreground(lipgloss.Color("11")).Render(out)
+		}
+		printed := t.AttemptsByStatus(parse.ActionFail)
+		if last := attempts[len(attempts)-1]; last.Status == "" {
+			printed = append(printed, last)
+		}
+		seen := make(map[string]bool)
+		for _, a := range printed {
+			block := strings.TrimSuffix(c.prepareStyledEvents(t, a.Events, a.Failures(), indent, tree, propagated), "\n")
+			if !seen[block] {
+				seen[block] = true
+				out += "\n\n" + block
+			}
+		}
+		out += "\n"
+	} else {
+		out = c.prepareStyledEvents(t, t.Events, t.Failures, indent, tree, propagated)
+	}
+	if t.Example != nil {
+		out += "\n\n" + c.prepareStyledExample(t.Example, indent)
+	}
+	if t.Fuzz != nil {
+		if !strings.HasSuffix(out, "\n") {
+			// Only the "--- FAIL" line was printed.
+			out += "\n"
+		}
+		out += "\n" + c.prepareStyledFuzz(t.Fuzz, indent)
+	}
+	if len(t.Artifacts) > 0 {
+		if !strings.HasSuffix(out, "\n") {
+			// Only the "--- FAIL" line was printed.
+			out += "\n\n"
+		}
+		out += c.prepareStyledArtifacts(t.Artifacts, indent)
+	}
+	return out
+}
+
+// prepareStyledArtifacts returns the directories a failed test stored output files in, see
+// t.ArtifactDir, so they are easy to find next to the failure.
+func (c *consoleWriter) prepareStyledArtifacts(dirs []string, indent string) string {
+	var rows strings.Builder
+	for _, dir := range dirs {
+		title := "artifacts:"
+		if c.format != OutputFormatMarkdown {
+			title = c.newStyle().Bold(true).Render(title)
+		}
+		rows.WriteString(indent + "    " + title + " " + dir + "\n")
+	}
+	return rows.String()
+}
 
+// prepareStyledEvents returns the output of a failed test, with the "--- FAIL" line on top. The
+// failures are those reported in the events, see prepareStyledFailure.
+func (c *consoleWriter) prepareStyledEvents(
+	t *parse.Test,
+	events []*parse.Event,
+	failures []*parse.Failure,
+	indent string,
+	tree bool,
+	propagated bool,
+) string {
 	var rows, headerRows strings.Builder
-	for _, e := range t.Events {
+	// The got and want sections of a failed Example are replaced with a diff, see
+	// prepareStyledExample.
+	var exampleOutput bool
+	// The raw output of testify and go-cmp assertions is replaced with a compact version, see
+	// prepareStyledAssertion.
+	assertions := make(map[*parse.Event]*parse.Assertion)
+	assertionOutput := make(map[*parse.Event]bool)
+	for _, a := range t.Assertions {
+		assertions[a.Events[0]] = a
+		for _, e := range a.Events {
+			assertionOutput[e] = true
+		}
+	}
+	// Failures reported with t.Error or t.Fatal are printed with their location on top, see
+	// prepareStyledFailure. Failures that are assertions are printed as such.
+	failureStart := make(map[*parse.Event]*parse.Failure)
+	failureOutput := make(map[*parse.Event]bool)
+	for _, f := range failures {
+		if assertionOutput[f.Events[0]] {
+			continue
+		}
+		failureStart[f.Events[0]] = f
+		for _, e := range f.Events {
+			failureOutput[e] = true
+		}
+	}
+	// Data race reports are printed once per package, see prepareStyledDataRaces.
+	raceOutput := make(map[*parse.Event]bool)
+	for _, r := range t.DataRaces {
+		for _, e := range r.Events {
+			raceOutput[e] = true
+		}
+	}
+	var finished bool
+	for _, e := range events {
 		// Only add events that have output information. Skip everything else.
 		// Note, since we know about all the output, we can bubble "--- Fail" to the top
 		// of the output so it's trivial to spot the failing test name and elapsed time.
 		if e.Action != parse.ActionOutput {
+			switch e.Action {
+			case parse.ActionPass, parse.ActionFail, parse.ActionSkip:
+				finished = true
+			}
 			continue
 		}
 		if strings.Contains(e.Output, failLine) {
+			// go test repeats the "--- FAIL" line of a fuzz test for the input that failed while
+			// fuzzing.
+			if t.Fuzz != nil && headerRows.Len() > 0 {
+				continue
+			}
 			header := strings.TrimSuffix(e.Output, "\n")
 			// go test prefixes too much padding to the "--- FAIL: " output lines.
 			// Let's cut the padding by half, being careful to preserve the fail
@@ -203,19 +624,77 @@ func (c *consoleWriter) prepareStyledTest(t *parse.Test) string {
 				}
 			}
 			header = pad + failLine + after
+			if tree {
+				// In tree mode the indentation reflects the depth of the test, not the padding
+				// added by go test.
+				header = indent + failLine + after
+			}
 
 			// Avoid colorizing markdown output so it renders properly, otherwise add a subtle
 			// red color to the test headers.
 			if c.format != OutputFormatMarkdown {
-				header = lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render(header)
+				style := c.newStyle().Foreground(lipgloss.Color("1"))
+				if propagated {
+					style = c.newStyle().Faint(true)
+				}
+				header = style.Render(header)
 			}
 			headerRows.WriteString(header)
 			continue
 		}
 
+		if a, ok := assertions[e]; ok {
+			rows.WriteString(c.prepareStyledAssertion(a, indent))
+			continue
+		}
+		if assertionOutput[e] {
+			continue
+		}
+		if f, ok := failureStart[e]; ok {
+			rows.WriteString(c.prepareStyledFailure(f, indent))
+			continue
+		}
+		if failureOutput[e] {
+			continue
+		}
+		if raceOutput[e] {
+			if e.IsRace() {
+				rows.WriteString(indent + "    WARNING: DATA RACE, see data races below\n")
+			}
+			continue
+		}
+		if t.Example != nil && e.Output == "got:\n" {
+			exampleOutput = true
+		}
+		if exampleOutput {
+			continue
+		}
+		// The bare name go test prints before running a benchmark adds nothing to the "--- FAIL"
+		// line.
+		if t.IsBenchmark() && isBenchmarkStart(t, e.Output) {
+			continue
+		}
+		// The fuzz block, see prepareStyledFuzz, replaces the lines go test prints about the
+		// failing input.
+		if t.Fuzz != nil && t.Fuzz.IsReproOutput(e.Output) {
+			continue
+		}
+		// The progress of the fuzzing engine, including minimizing the failing input, is noise
+		// once the test failed.
+		if e.IsFuzzProgress() {
+			continue
+		}
 		if e.Output != "" {
-			rows.WriteString(e.Output)
+			rows.WriteString(indent + e.Output)
+		}
+	}
+	if headerRows.Len() == 0 && !finished {
+		// A test that never finished has no "--- FAIL" line, add one to tell it apart.
+		header := indent + "--- INCOMPLETE: " + t.Name
+		if c.format != OutputFormatMarkdown {
+			header = c.newStyle().Foreground(lipgloss.Color("3")).Render(header)
 		}
+		headerRows.WriteString(header)
 	}
 	out := headerRows.String()
 	if rows.Len() > 0 {
@@ -223,3 +702,142 @@ func (c *consoleWriter) prepareStyledTest(t *parse.Test) string {
 	}
 	return out
 }
+
+// isBenchmarkStart reports whether the output is the bare name go test prints before running a
+// benchmark, e.g., "BenchmarkFoo" or "BenchmarkFoo-8" with -cpu.
+func isBenchmarkStart(t *parse.Test, output string) bool {
+	name := strings.TrimSpace(output)
+	if name == t.Name {
+		return true
+	}
+	procs, ok := strings.CutPrefix(name, t.Name+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(procs)
+	return err == nil
+}
+
+// prepareStyledFailure returns a failure reported with t.Error or t.Fatal, with the file:line
+// location on a line of its own followed by the message, so the location is easy to spot and
+// open. Markdown output is not styled.
+func (c *consoleWriter) prepareStyledFailure(f *parse.Failure, indent string) string {
+	// Keep the indentation go test uses for the output of (sub)tests.
+	output := f.Events[0].Output
+	indent += output[:len(output)-len(strings.TrimLeft(output, " "))]
+
+	location := f.File + ":" + strconv.Itoa(f.Line)
+	if c.format != OutputFormatMarkdown {
+		location = c.newStyle().Bold(true).Render(location)
+	}
+	var b strings.Builder
+	b.WriteString(indent + location + "\n")
+	for _, line := range strings.Split(f.Message, "\n") {
+		b.WriteString(indent + "    " + line + "\n")
+	}
+	return b.String()
+}
+
+// prepareStyledAssertion returns a failed testify or go-cmp assertion, with the location and
+// message on the first line followed by the diff, or the expected and actual values if there is no
+// diff. Markdown output is not colorized.
+func (c *consoleWriter) prepareStyledAssertion(a *parse.Assertion, indent string) string {
+	removed := c.newStyle().Foreground(lipgloss.Color("9"))
+	added := c.newStyle().Foreground(lipgloss.Color("10"))
+	style := func(style lipgloss.Style, s string) string {
+		if c.format == OutputFormatMarkdown {
+			return s
+		}
+		return style.Render(s)
+	}
+	// Keep the indentation go test uses for the output of (sub)tests.
+	output := a.Events[0].Output
+	indent += output[:len(output)-len(strings.TrimLeft(output, " "))]
+
+	message, details, _ := strings.Cut(a.Message, "\n")
+	message = strings.TrimSuffix(message, ":")
+	if a.Messages != "" {
+		message += ": " + a.Messages
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s%s:%d: %s\n", indent, a.File, a.Line, message)
+	indent += "    "
+	if details != "" {
+		for _, line := range strings.Split(details, "\n") {
+			b.WriteString(indent + line + "\n")
+		}
+	}
+	switch {
+	case len(a.Diff) > 0:
+		for _, line := range a.Diff {
+			var row string
+			switch line.Op {
+			case parse.DiffRemoved:
+				row = style(removed, "- "+line.Text)
+			case parse.DiffAdded:
+				row = style(added, "+ "+line.Text)
+			default:
+				row = "  " + line.Text
+			}
+			b.WriteString(indent + row + "\n")
+		}
+	case a.Expected != "" || a.Actual != "":
+		b.WriteString(indent + style(removed, "expected: "+a.Expected) + "\n")
+		b.WriteString(indent + style(added, "actual  : "+a.Actual) + "\n")
+	}
+	return b.String()
+}
+
+// prepareStyledExample returns a line diff of the expected and actual output of a failed Example.
+// Markdown output is not colorized, the +/- prefixes are enough to read the diff.
+func (c *consoleWriter) prepareStyledExample(e *parse.ExampleFailure, indent string) string {
+	removed := c.newStyle().Foreground(lipgloss.Color("9"))
+	added := c.newStyle().Foreground(lipgloss.Color("10"))
+
+	var b strings.Builder
+	header := "--- want"
+	if e.Unordered {
+		header += " (unordered)"
+	}
+	b.WriteString(indent + header + "\n" + indent + "+++ got\n")
+	for _, line := range e.Diff() {
+		var row string
+		switch line.Op {
+		case parse.DiffRemoved:
+			row = "- " + line.Text
+			if c.format != OutputFormatMarkdown {
+				row = removed.Render(row)
+			}
+		case parse.DiffAdded:
+			row = "+ " + line.Text
+			if c.format != OutputFormatMarkdown {
+				row = added.Render(row)
+			}
+		default:
+			row = "  " + line.Text
+		}
+		b.WriteString(indent + row + "\n")
+	}
+	return b.String()
+}
+
+// prepareStyledFuzz returns the failing input of a fuzz test and the command to reproduce it.
+func (c *consoleWriter) prepareStyledFuzz(f *parse.FuzzFailure, indent string) string {
+	input := f.Entry
+	if f.CorpusPath != "" {
+		input = f.CorpusPath
+	}
+	lines := []string{
+		indent + "Failing input (" + string(f.Phase) + "): " + input,
+		indent + "To re-run: " + f.RunCommand(),
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		// Render each line on its own, lipgloss pads the lines of a block to the same width.
+		if c.format != OutputFormatMarkdown {
+			line = c.newStyle().Foreground(lipgloss.Color("11")).Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
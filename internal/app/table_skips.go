@@ -0,0 +1,115 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+
+	"github.com/mfridman/tparse/internal/utils"
+	"github.com/mfridman/tparse/parse"
+)
+
+// skipReasonsTable prints the skipped tests of all packages grouped by the reason they were
+// skipped for, the most common reason first. The reason and count are only printed on the first
+// row of each group. Nothing is printed if no tests were skipped.
+func (c *consoleWriter) skipReasonsTable(packages []*parse.Package, option TestTableOptions) {
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Summary.Package)
+	}
+	packagePrefix := utils.FindLongestCommonPrefix(names)
+
+	groups := make(map[string][]*parse.Test)
+	for _, pkg := range packages {
+		// Discard packages where we cannot generate a sensible test summary.
+		if pkg.NoTestFiles || pkg.NoTests || pkg.HasPanic {
+			continue
+		}
+		for _, t := range filterAttrs(pkg.TestsByAction(parse.ActionSkip), option.Attrs) {
+			reason := t.SkipReason()
+			groups[reason] = append(groups[reason], t)
+		}
+	}
+	if len(groups) == 0 {
+		return
+	}
+	reasons := make([]string, 0, len(groups))
+	for reason := range groups {
+		reasons = append(reasons, reason)
+	}
+	sort.Slice(reasons, func(i, j int) bool {
+		if n, m := len(groups[reasons[i]]), len(groups[reasons[j]]); n != m {
+			return n > m
+		}
+		return reasons[i] < reasons[j]
+	})
+
+	tbl := c.newTable(func(style lipgloss.Style, row, col int) lipgloss.Style {
+		switch row {
+		case table.HeaderRow:
+		default:
+			if col != 1 {
+				// Reason, test name and package name
+				style = style.Align(lipgloss.Left)
+			}
+		}
+		return style
+	})
+	tbl.Headers("Reason", "Skipped", "Test", "Package")
+	data := table.NewStringData()
+	for _, reason := range reasons {
+		tests := groups[reason]
+		sort.SliceStable(tests, func(i, j int) bool {
+			if tests[i].Package != tests[j].Package {
+				return tests[i].Package < tests[j].Package
+			}
+			return tests[i].Name < tests[j].Name
+		})
+		for i, t := range tests {
+			var row []string
+			if i == 0 {
+				row = []string{formatSkipReason(reason, 64), strconv.Itoa(len(tests))}
+			} else {
+				row = []string{"", ""}
+			}
+			row = append(row,
+				shortenTestName(t.Name, option.Trim, 32),
+				shortenPackageName(t.Package, packagePrefix, 16, option.Trim, option.TrimPath),
+			)
+			data.Append(row)
+		}
+	}
+	fmt.Fprintln(c, tbl.Data(data).Render())
+	if c.format == OutputFormatMarkdown {
+		// Separate the markdown table from whatever follows, otherwise the tables are merged.
+		fmt.Fprintln(c)
+	}
+}
+
+// hasSkipReason reports whether skipped tests are displayed, and any of them has a skip reason.
+func hasSkipReason(packages []*parse.Package, option TestTableOptions) bool {
+	if !option.Skip {
+		return false
+	}
+	for _, pkg := range packages {
+		for _, t := range filterAttrs(pkg.TestsByAction(parse.ActionSkip), option.Attrs) {
+			if t.SkipReason() != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// formatSkipReason returns the skip reason on a single line, shortened to at most n runes. Tests
+// skipped without a reason are marked as such.
+func formatSkipReason(reason string, n int) string {
+	if reason == "" {
+		return "--"
+	}
+	return truncate(strings.Join(strings.Fields(reason), " "), n)
+}
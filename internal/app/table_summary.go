@@ -5,6 +5,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
@@ -33,7 +34,7 @@ func (c *consoleWriter) summaryTable(
 	options SummaryTableOptions,
 	against *parse.GoTestSummary,
 ) {
-	tbl := newTable(c.format, func(style lipgloss.Style, row, col int) lipgloss.Style {
+	tbl := c.newTable(func(style lipgloss.Style, row, col int) lipgloss.Style {
 		switch row {
 		case table.HeaderRow:
 		default:
@@ -52,8 +53,16 @@ func (c *consoleWriter) summaryTable(
 		pass:        "Pass",
 		fail:        "Fail",
 		skip:        "Skip",
+		incomplete:  "Incomplete",
 	}
-	tbl.Headers(header.toRow()...)
+	// Tests that never finished are rare, only add a column for them if there are any.
+	var showIncomplete bool
+	for _, pkg := range packages {
+		if !pkg.HasPanic && len(pkg.TestsByAction(parse.ActionIncomplete)) > 0 {
+			showIncomplete = true
+		}
+	}
+	tbl.Headers(header.toRow(showIncomplete)...)
 	data := table.NewStringData()
 
 	// Capture as separate slices because notests are optional when passed tests are available.
@@ -79,19 +88,29 @@ func (c *consoleWriter) summaryTable(
 				status:      c.red("PANIC"),
 				elapsed:     elapsed,
 				packageName: packageName,
-				cover:       "--", pass: "--", fail: "--", skip: "--",
+				cover:       "--", pass: "--", fail: "--", skip: "--", incomplete: "--",
 			}
-			data.Append(row.toRow())
+			data.Append(row.toRow(showIncomplete))
 			continue
 		}
 		if pkg.HasFailedBuildOrSetup {
+			packageName += "\n[" + pkg.Summary.Output + "]"
+			// Show the first compiler error, the failure section has the full build output.
+			if pkg.FailedBuild != nil {
+				if errs := pkg.FailedBuild.Errors(); len(errs) > 0 {
+					packageName += "\n" + truncateBuildError(errs[0], 48)
+					if len(errs) > 1 {
+						packageName += fmt.Sprintf("\n(+%d more)", len(errs)-1)
+					}
+				}
+			}
 			row := summaryRow{
 				status:      c.red("FAIL"),
 				elapsed:     elapsed,
-				packageName: packageName + "\n[" + pkg.Summary.Output + "]",
-				cover:       "--", pass: "--", fail: "--", skip: "--",
+				packageName: packageName,
+				cover:       "--", pass: "--", fail: "--", skip: "--", incomplete: "--",
 			}
-			data.Append(row.toRow())
+			data.Append(row.toRow(showIncomplete))
 			continue
 		}
 		if pkg.NoTestFiles {
@@ -99,7 +118,7 @@ func (c *consoleWriter) summaryTable(
 				status:      c.yellow("NOTEST"),
 				elapsed:     elapsed,
 				packageName: packageName + "\n[no test files]",
-				cover:       "--", pass: "--", fail: "--", skip: "--",
+				cover:       "--", pass: "--", fail: "--", skip: "--", incomplete: "--",
 			}
 			notests = append(notests, row)
 			continue
@@ -111,7 +130,7 @@ func (c *consoleWriter) summaryTable(
 					status:      c.yellow("NOTEST"),
 					elapsed:     elapsed,
 					packageName: packageName + "\n[no tests to run]",
-					cover:       "--", pass: "--", fail: "--", skip: "--",
+					cover:       "--", pass: "--", fail: "--", skip: "--", incomplete: "--",
 				}
 				notests = append(notests, row)
 				continue
@@ -127,7 +146,7 @@ func (c *consoleWriter) summaryTable(
 				status:      c.yellow("NOTEST"),
 				elapsed:     elapsed,
 				packageName: packageName,
-				cover:       "--", pass: "--", fail: "--", skip: "--",
+				cover:       "--", pass: "--", fail: "--", skip: "--", incomplete: "--",
 			}
 			notests = append(notests, row)
 
@@ -168,9 +187,21 @@ func (c *consoleWriter) summaryTable(
 		}
 
 		status := c.FormatAction(pkg.Summary.Action)
+		if pkg.Summary.Action == "" {
+			// The package never finished, e.g., the output was truncated.
+			status = c.FormatAction(parse.ActionIncomplete)
+		}
+		if pkg.Timeout != nil {
+			status = c.red("TIMEOUT")
+		}
+
+		passCount := len(pkg.TestsByAction(parse.ActionPass))
+		failCount := len(pkg.TestsByAction(parse.ActionFail))
+		skipCount := len(pkg.TestsByAction(parse.ActionSkip))
+		incompleteCount := len(pkg.TestsByAction(parse.ActionIncomplete))
 
 		// Skip packages with no coverage to mimic nocoverageredesign behavior (changed in github.com/golang/go/issues/24570)
-		totalTests := len(pkg.TestsByAction(parse.ActionPass)) + len(pkg.TestsByAction(parse.ActionFail)) + len(pkg.TestsByAction(parse.ActionSkip))
+		totalTests := passCount + failCount + skipCount + incompleteCount
 		if pkg.Cover && pkg.Coverage == 0.0 && totalTests == 0 {
 			continue
 		}
@@ -180,9 +211,10 @@ func (c *consoleWriter) summaryTable(
 			elapsed:     elapsed,
 			packageName: packageName,
 			cover:       coverage,
-			pass:        strconv.Itoa(len(pkg.TestsByAction(parse.ActionPass))),
-			fail:        strconv.Itoa(len(pkg.TestsByAction(parse.ActionFail))),
-			skip:        strconv.Itoa(len(pkg.TestsByAction(parse.ActionSkip))),
+			pass:        strconv.Itoa(passCount),
+			fail:        strconv.Itoa(failCount),
+			skip:        strconv.Itoa(skipCount),
+			incomplete:  strconv.Itoa(incompleteCount),
 		}
 		passed = append(passed, row)
 	}
@@ -191,7 +223,7 @@ func (c *consoleWriter) summaryTable(
 		return
 	}
 	for _, r := range passed {
-		data.Append(r.toRow())
+		data.Append(r.toRow(showIncomplete))
 	}
 
 	// Only display the "no tests to run" cases if users want to see them when passed
@@ -200,7 +232,7 @@ func (c *consoleWriter) summaryTable(
 	// package. This is almost always because the user forgot to match one or more packages.
 	if showNoTests || (len(passed) == 0 && len(notests) == 1) {
 		for _, r := range notests {
-			data.Append(r.toRow())
+			data.Append(r.toRow(showIncomplete))
 		}
 	}
 
@@ -215,10 +247,13 @@ type summaryRow struct {
 	pass        string
 	fail        string
 	skip        string
+	incomplete  string
 }
 
-func (r summaryRow) toRow() []string {
-	return []string{
+// toRow returns the columns of the row. The incomplete column is only included if incomplete is
+// true.
+func (r summaryRow) toRow(incomplete bool) []string {
+	row := []string{
 		r.status,
 		r.elapsed,
 		r.packageName,
@@ -227,6 +262,10 @@ func (r summaryRow) toRow() []string {
 		r.fail,
 		r.skip,
 	}
+	if incomplete {
+		row = append(row, r.incomplete)
+	}
+	return row
 }
 
 func shortenPackageName(
@@ -262,3 +301,26 @@ func shortenPackageName(
 
 	return name
 }
+
+// truncate shortens s to at most n runes, replacing the remainder with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+// truncateBuildError shortens a compiler error, e.g., "b.go:3:23: cannot use ...", to at most n
+// runes without cutting its file:line:col location, which is kept whole even if it is longer.
+func truncateBuildError(s string, n int) string {
+	location, message, ok := strings.Cut(s, ": ")
+	if !ok {
+		return truncate(s, n)
+	}
+	room := n - utf8.RuneCountInString(location+": ")
+	if room < 2 {
+		return location + ": …"
+	}
+	return location + ": " + truncate(message, room)
+}
@@ -34,6 +34,22 @@ type TestTableOptions struct {
 	// Display up to N slow tests for each package, tests are sorted by
 	// calculated the elapsed time for the given test.
 	Slow int
+
+	// Tree lists subtests under their parent test, with names indented relative to the parent
+	// instead of repeating the full test name.
+	Tree bool
+
+	// GroupSkips displays skipped tests grouped by the reason they were skipped for, the most
+	// common reason first.
+	GroupSkips bool
+
+	// Attrs limits the tests to those with all of the given attributes, set with t.Attr. Subtests
+	// inherit the attributes of their parent, see parse.Test.Attr.
+	Attrs map[string]string
+
+	// GroupAttr displays the number of tests by status for each value of the given attribute, e.g.,
+	// "owner".
+	GroupAttr string
 }
 
 type packageTests struct {
@@ -43,16 +59,19 @@ type packageTests struct {
 	passed       []*parse.Test
 	failed       []*parse.Test
 	failedCount  int
+	// incomplete holds the tests that ran but never finished.
+	incomplete      []*parse.Test
+	incompleteCount int
 }
 
 func (c *consoleWriter) testsTable(packages []*parse.Package, option TestTableOptions) {
 	// Print passed tests, sorted by elapsed DESC. Grouped by alphabetically sorted packages.
-	tbl := newTable(c.format, func(style lipgloss.Style, row, col int) lipgloss.Style {
+	tbl := c.newTable(func(style lipgloss.Style, row, col int) lipgloss.Style {
 		switch row {
 		case table.HeaderRow:
 		default:
-			if col == 2 || col == 3 {
-				// Test name and package name
+			if col >= 2 {
+				// Test name, package name and skip reason
 				style = style.Align(lipgloss.Left)
 			}
 		}
@@ -63,8 +82,10 @@ func (c *consoleWriter) testsTable(packages []*parse.Package, option TestTableOp
 		elapsed:     "Elapsed",
 		testName:    "Test",
 		packageName: "Package",
+		reason:      "Reason",
 	}
-	tbl.Headers(header.toRow()...)
+	showReason := hasSkipReason(packages, option)
+	tbl.Headers(header.toRow(showReason)...)
 	data := table.NewStringData()
 
 	names := make([]string, 0, len(packages))
@@ -79,17 +100,19 @@ func (c *consoleWriter) testsTable(packages []*parse.Package, option TestTableOp
 			continue
 		}
 		pkgTests := getTestsFromPackages(pkg, option)
-		all := make([]*parse.Test, 0, len(pkgTests.passed)+len(pkgTests.skipped)+len(pkgTests.failed))
+		all := make([]*parse.Test, 0, len(pkgTests.passed)+len(pkgTests.skipped)+len(pkgTests.failed)+len(pkgTests.incomplete))
 		all = append(all, pkgTests.passed...)
 		all = append(all, pkgTests.skipped...)
 		all = append(all, pkgTests.failed...)
+		all = append(all, pkgTests.incomplete...)
+		names := testNames(pkg, all, option.Tree)
 
 		for _, t := range all {
-			// TODO(mf): why are we sorting this?
-			t.SortEvents()
-
-			testName := shortenTestName(t.Name, option.Trim, 32)
+			testName := shortenTestName(names[t], option.Trim, 32)
 
+			if len(t.Attempts()) > 1 {
+				testName += " " + c.formatAttempts(t)
+			}
 			status := c.FormatAction(t.Status())
 			packageName := shortenPackageName(t.Package, packagePrefix, 16, option.Trim, option.TrimPath)
 
@@ -99,11 +122,14 @@ func (c *consoleWriter) testsTable(packages []*parse.Package, option TestTableOp
 				testName:    testName,
 				packageName: packageName,
 			}
-			data.Append(row.toRow())
+			if t.Status() == parse.ActionSkip {
+				row.reason = formatSkipReason(t.SkipReason(), 48)
+			}
+			data.Append(row.toRow(showReason))
 		}
 		if i != (len(packages) - 1) {
 			// Add a blank row between packages.
-			data.Append(testRow{}.toRow())
+			data.Append(testRow{}.toRow(showReason))
 		}
 	}
 
@@ -115,12 +141,12 @@ func (c *consoleWriter) testsTable(packages []*parse.Package, option TestTableOp
 func (c *consoleWriter) testsTableMarkdown(packages []*parse.Package, option TestTableOptions) {
 	for _, pkg := range packages {
 		// Print passed tests, sorted by elapsed DESC. Grouped by alphabetically sorted packages.
-		tbl := newTable(c.format, func(style lipgloss.Style, row, col int) lipgloss.Style {
+		tbl := c.newTable(func(style lipgloss.Style, row, col int) lipgloss.Style {
 			switch row {
 			case table.HeaderRow:
 			default:
-				if col == 2 {
-					// Test name
+				if col >= 2 {
+					// Test name and skip reason
 					style = style.Align(lipgloss.Left)
 				}
 			}
@@ -131,6 +157,10 @@ func (c *consoleWriter) testsTableMarkdown(packages []*parse.Package, option Tes
 			"Elapsed",
 			"Test",
 		}
+		showReason := hasSkipReason([]*parse.Package{pkg}, option)
+		if showReason {
+			header = append(header, "Reason")
+		}
 		tbl.Headers(header...)
 		data := table.NewStringData()
 
@@ -139,23 +169,33 @@ func (c *consoleWriter) testsTableMarkdown(packages []*parse.Package, option Tes
 			continue
 		}
 		pkgTests := getTestsFromPackages(pkg, option)
-		all := make([]*parse.Test, 0, len(pkgTests.passed)+len(pkgTests.skipped)+len(pkgTests.failed))
+		all := make([]*parse.Test, 0, len(pkgTests.passed)+len(pkgTests.skipped)+len(pkgTests.failed)+len(pkgTests.incomplete))
 		all = append(all, pkgTests.passed...)
 		all = append(all, pkgTests.skipped...)
 		all = append(all, pkgTests.failed...)
+		all = append(all, pkgTests.incomplete...)
+		names := testNames(pkg, all, option.Tree)
 
 		for _, t := range all {
-			// TODO(mf): why are we sorting this?
-			t.SortEvents()
-
-			testName := shortenTestName(t.Name, option.Trim, 32)
+			testName := shortenTestName(names[t], option.Trim, 32)
 
+			if len(t.Attempts()) > 1 {
+				testName += " " + c.formatAttempts(t)
+			}
 			status := c.FormatAction(t.Status())
-			data.Append([]string{
+			row := []string{
 				status,
 				strconv.FormatFloat(t.Elapsed(), 'f', 2, 64),
 				testName,
-			})
+			}
+			if showReason {
+				var reason string
+				if t.Status() == parse.ActionSkip {
+					reason = formatSkipReason(t.SkipReason(), 48)
+				}
+				row = append(row, reason)
+			}
+			data.Append(row)
 		}
 		if data.Rows() > 0 {
 			fmt.Fprintf(c, "## 📦 Package **`%s`**\n", pkg.Summary.Package)
@@ -166,6 +206,9 @@ func (c *consoleWriter) testsTableMarkdown(packages []*parse.Package, option Tes
 				pkgTests.skippedCount,
 				pkgTests.failedCount,
 			)
+			if pkgTests.incompleteCount > 0 {
+				msg = strings.TrimSuffix(msg, "\n") + fmt.Sprintf(" | %d incomplete\n", pkgTests.incompleteCount)
+			}
 			if option.Slow > 0 && option.Slow < pkgTests.passedCount {
 				msg += fmt.Sprintf("↓ Slowest %d passed tests shown (of %d)\n",
 					option.Slow,
@@ -189,11 +232,11 @@ func (c *consoleWriter) testsTableMarkdown(packages []*parse.Package, option Tes
 
 func getTestsFromPackages(pkg *parse.Package, option TestTableOptions) *packageTests {
 	tests := &packageTests{}
-	skipped := pkg.TestsByAction(parse.ActionSkip)
+	skipped := filterAttrs(pkg.TestsByAction(parse.ActionSkip), option.Attrs)
 	tests.skippedCount = len(skipped)
-	passed := pkg.TestsByAction(parse.ActionPass)
+	passed := filterAttrs(pkg.TestsByAction(parse.ActionPass), option.Attrs)
 	tests.passedCount = len(passed)
-	failed := pkg.TestsByAction(parse.ActionFail)
+	failed := filterAttrs(pkg.TestsByAction(parse.ActionFail), option.Attrs)
 	tests.failedCount = len(failed)
 	if option.Skip {
 		tests.skipped = append(tests.skipped, skipped...)
@@ -210,9 +253,64 @@ func getTestsFromPackages(pkg *parse.Package, option TestTableOptions) *packageT
 		}
 	}
 	tests.failed = append(tests.failed, failed...)
+	tests.incomplete = filterAttrs(pkg.TestsByAction(parse.ActionIncomplete), option.Attrs)
+	tests.incompleteCount = len(tests.incomplete)
 	return tests
 }
 
+// testNames returns the display name for each of the given tests. When tree is true, tests are
+// reordered in place so that subtests follow their parent, and a subtest whose parent is also
+// displayed is named relative to the parent and indented by its depth.
+func testNames(pkg *parse.Package, tests []*parse.Test, tree bool) map[*parse.Test]string {
+	names := make(map[*parse.Test]string, len(tests))
+	if !tree {
+		for _, t := range tests {
+			names[t] = t.Name
+		}
+		return names
+	}
+	selected := make(map[*parse.Test]bool, len(tests))
+	for _, t := range tests {
+		selected[t] = true
+	}
+	ordered := tests[:0]
+	depth := make(map[*parse.Test]int, len(tests))
+	pkg.Walk(func(t *parse.Test) bool {
+		if !selected[t] {
+			return true
+		}
+		ordered = append(ordered, t)
+		if t.Parent != nil && selected[t.Parent] {
+			depth[t] = depth[t.Parent] + 1
+			names[t] = strings.Repeat("  ", depth[t]) + t.ShortName()
+		} else {
+			names[t] = t.Name
+		}
+		return true
+	})
+	return names
+}
+
+// attemptSummary reports how many attempts of a test that ran more than once passed, e.g., "3/5
+// passed", and whether the outcomes differ across attempts.
+func attemptSummary(t *parse.Test) string {
+	summary := fmt.Sprintf("%d/%d passed", len(t.AttemptsByStatus(parse.ActionPass)), len(t.Attempts()))
+	if t.IsFlaky() {
+		summary = "flaky, " + summary
+	}
+	return summary
+}
+
+// formatAttempts returns the attempt summary of a test for the tests table, highlighting flaky
+// tests.
+func (c *consoleWriter) formatAttempts(t *parse.Test) string {
+	summary := "(" + attemptSummary(t) + ")"
+	if t.IsFlaky() {
+		return c.yellow(summary)
+	}
+	return summary
+}
+
 func shortenTestName(s string, trim bool, maxLength int) string {
 	var testName strings.Builder
 	testName.WriteString(s)
@@ -240,13 +338,19 @@ type testRow struct {
 	elapsed     string
 	testName    string
 	packageName string
+	reason      string
 }
 
-func (r testRow) toRow() []string {
-	return []string{
+// toRow returns the columns of the row. The reason column is only included if reason is true.
+func (r testRow) toRow(reason bool) []string {
+	row := []string{
 		r.status,
 		r.elapsed,
 		r.testName,
 		r.packageName,
 	}
+	if reason {
+		row = append(row, r.reason)
+	}
+	return row
 }
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+
+	"github.com/mfridman/tparse/internal/utils"
+	"github.com/mfridman/tparse/parse"
+)
+
+type TimelineTableOptions struct {
+	// For narrow screens, trim long test and package names vertically. See
+	// TestTableOptions.Trim.
+	Trim bool
+
+	// TrimPath is the path prefix to trim from the package name.
+	TrimPath string
+}
+
+// timelineWidth is the number of characters of a timeline bar.
+const timelineWidth = 32
+
+// timelineTables prints when each test ran, relative to the start of its package, along with how
+// long it ran and how long it was paused waiting to run. It is followed by the concurrency of each
+// package: the peak number of tests that ran at the same time, and how long serial and parallel
+// tests ran. Tests without subtests are counted, see parse.Package.PeakConcurrency.
+//
+// Packages without event times, e.g., converted from go test output without -json, are left out.
+func (c *consoleWriter) timelineTables(packages []*parse.Package, option TimelineTableOptions) {
+	names := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		names = append(names, pkg.Summary.Package)
+	}
+	packagePrefix := utils.FindLongestCommonPrefix(names)
+
+	tests := c.newTable(func(style lipgloss.Style, row, col int) lipgloss.Style {
+		switch row {
+		case table.HeaderRow:
+		default:
+			switch col {
+			case 0, 4, 5:
+				// Test name, timeline and package name
+				style = style.Align(lipgloss.Left)
+			case 1, 2, 3:
+				style = style.Align(lipgloss.Right)
+			}
+		}
+		return style
+	})
+	tests.Headers("Test", "Start", "Running", "Paused", "Timeline", "Package")
+	testsData := table.NewStringData()
+
+	concurrency := c.newTable(func(style lipgloss.Style, row, col int) lipgloss.Style {
+		switch row {
+		case table.HeaderRow:
+		default:
+			if col == 0 {
+				// Package name
+				style = style.Align(lipgloss.Left)
+			}
+		}
+		return style
+	})
+	concurrency.Headers("Package", "Elapsed", "Peak", "Serial", "Parallel", "Paused")
+	concurrencyData := table.NewStringData()
+
+	for _, pkg := range packages {
+		timings := make(map[*parse.Test]parse.Timing, len(pkg.Tests))
+		var start, end time.Time
+		for _, t := range pkg.Tests {
+			timing := t.Timing()
+			if timing.Start.IsZero() {
+				continue
+			}
+			timings[t] = timing
+			if start.IsZero() || timing.Start.Before(start) {
+				start = timing.Start
+			}
+			if timing.End.After(end) {
+				end = timing.End
+			}
+		}
+		if len(timings) == 0 {
+			continue
+		}
+		if !pkg.StartTime.IsZero() && pkg.StartTime.Before(start) {
+			start = pkg.StartTime
+		}
+		packageName := shortenPackageName(pkg.Summary.Package, packagePrefix, 16, option.Trim, option.TrimPath)
+
+		ordered := make([]*parse.Test, 0, len(timings))
+		for t := range timings {
+			ordered = append(ordered, t)
+		}
+		sort.Slice(ordered, func(i, j int) bool {
+			a, b := timings[ordered[i]], timings[ordered[j]]
+			if !a.Start.Equal(b.Start) {
+				return a.Start.Before(b.Start)
+			}
+			return ordered[i].Name < ordered[j].Name
+		})
+		if testsData.Rows() > 0 {
+			// Add a blank row between packages.
+			testsData.Append(make([]string, 6))
+		}
+		var serial, parallel, paused time.Duration
+		for _, t := range ordered {
+			timing := timings[t]
+			testsData.Append([]string{
+				shortenTestName(t.Name, option.Trim, 32),
+				"+" + formatSeconds(timing.Start.Sub(start)),
+				formatSeconds(timing.Running),
+				formatSeconds(timing.Paused),
+				timelineBar(timing, start, end),
+				packageName,
+			})
+			if len(t.Children) > 0 {
+				continue
+			}
+			if t.IsParallel() {
+				parallel += timing.Running
+			} else {
+				serial += timing.Running
+			}
+			paused += timing.Paused
+		}
+		concurrencyData.Append([]string{
+			packageName,
+			formatSeconds(end.Sub(start)),
+			strconv.Itoa(pkg.PeakConcurrency()),
+			formatSeconds(serial),
+			formatSeconds(parallel),
+			formatSeconds(paused),
+		})
+	}
+	if testsData.Rows() == 0 {
+		return
+	}
+	fmt.Fprintln(c, tests.Data(testsData).Render())
+	if c.format == OutputFormatMarkdown {
+		// Separate the markdown table from whatever follows, otherwise the tables are merged.
+		fmt.Fprintln(c)
+	}
+	fmt.Fprintln(c, concurrency.Data(concurrencyData).Render())
+	if c.format == OutputFormatMarkdown {
+		fmt.Fprintln(c)
+	}
+}
+
+// timelineBar returns a bar that spans the time between start and end, marking when the test ran
+// with "█" and when it was paused with "░".
+func timelineBar(timing parse.Timing, start, end time.Time) string {
+	total := end.Sub(start)
+	if total <= 0 {
+		return strings.Repeat("█", timelineWidth)
+	}
+	// cell returns the index of the cell that contains t.
+	cell := func(t time.Time) int {
+		return min(int(t.Sub(start)*timelineWidth/total), timelineWidth-1)
+	}
+	bar := []rune(strings.Repeat(" ", timelineWidth))
+	for i := cell(timing.Start); i <= cell(timing.End); i++ {
+		bar[i] = '░'
+	}
+	for _, r := range timing.Runs {
+		for i := cell(r.Start); i <= cell(r.End); i++ {
+			bar[i] = '█'
+		}
+	}
+	return string(bar)
+}
+
+// formatSeconds formats a duration in seconds, like the elapsed time of tests and packages.
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 2, 64) + "s"
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// OpenFile opens the named file for reading. Files compressed with gzip or zstd are detected by
+// their magic bytes, regardless of the file extension, and decompressed transparently.
+func OpenFile(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return r, nil
+}
+
+// NewReader returns a reader that decompresses rc if it's compressed with gzip or zstd, and reads
+// it as-is otherwise. Closing the returned reader closes rc.
+func NewReader(rc io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(rc)
+	magic, err := br.Peek(len(zstdMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return &readCloser{Reader: zr, closers: []io.Closer{zr, rc}}, nil
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return &readCloser{Reader: zr, closers: []io.Closer{zr.IOReadCloser(), rc}}, nil
+	}
+	return &readCloser{Reader: br, closers: []io.Closer{rc}}, nil
+}
+
+// CreateFile creates the named file for writing. If the name ends in .gz, the written data is
+// compressed with gzip. The compressed stream is only complete once the file is closed.
+func CreateFile(name string) (io.WriteCloser, error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(name, ".gz") {
+		return f, nil
+	}
+	zw := gzip.NewWriter(f)
+	return &writeCloser{Writer: zw, closers: []io.Closer{zw, f}}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *readCloser) Close() error {
+	return closeAll(r.closers)
+}
+
+type writeCloser struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (w *writeCloser) Close() error {
+	return closeAll(w.closers)
+}
+
+// closeAll closes each closer in order, e.g., a decompressor before the underlying file.
+func closeAll(closers []io.Closer) error {
+	var errs []error
+	for _, c := range closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestOpenFile(t *testing.T) {
+	t.Parallel()
+
+	const content = `{"Action":"pass","Package":"example.com/foo"}` + "\n"
+	dir := t.TempDir()
+	write := func(t *testing.T, name string, compress func(w io.Writer) io.WriteCloser) string {
+		t.Helper()
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		w := compress(f)
+		if _, err := io.WriteString(w, content); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return f.Name()
+	}
+
+	tests := []struct {
+		name     string
+		compress func(w io.Writer) io.WriteCloser
+	}{
+		{
+			name:     "plain.jsonl",
+			compress: func(w io.Writer) io.WriteCloser { return WriteNopCloser{Writer: w} },
+		},
+		{
+			name:     "gzip.jsonl.gz",
+			compress: func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		},
+		{
+			// Detected by magic bytes, not by extension.
+			name:     "gzip.jsonl",
+			compress: func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+		},
+		{
+			name: "zstd.jsonl.zst",
+			compress: func(w io.Writer) io.WriteCloser {
+				zw, err := zstd.NewWriter(w)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return zw
+			},
+		},
+		{
+			name:     "empty.jsonl",
+			compress: func(w io.Writer) io.WriteCloser { return WriteNopCloser{Writer: io.Discard} },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := write(t, tt.name, tt.compress)
+			r, err := OpenFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := content
+			if tt.name == "empty.jsonl" {
+				want = ""
+			}
+			if string(got) != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestCreateFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"follow.out", "follow.out.gz"} {
+		path := filepath.Join(dir, name)
+		w, err := CreateFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(w, "=== RUN   TestFoo\n"); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if compressed := len(raw) > 1 && raw[0] == 0x1f && raw[1] == 0x8b; compressed != (filepath.Ext(name) == ".gz") {
+			t.Errorf("%s: compressed = %v", name, compressed)
+		}
+		r, err := OpenFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "=== RUN   TestFoo\n" {
+			t.Errorf("%s: got %q", name, got)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"runtime/debug"
+	"strings"
 
 	"github.com/mfridman/tparse/internal/app"
 	"github.com/mfridman/tparse/internal/utils"
@@ -27,7 +28,6 @@ var (
 	smallScreenPtr  = flag.Bool("smallscreen", false, "")
 	noColorPtr      = flag.Bool("nocolor", false, "")
 	slowPtr         = flag.Int("slow", 0, "")
-	fileNamePtr     = flag.String("file", "", "")
 	formatPtr       = flag.String("format", "", "")
 	followPtr       = flag.Bool("follow", false, "")
 	followOutputPtr = flag.String("follow-output", "", "")
@@ -35,6 +35,13 @@ var (
 	progressPtr     = flag.Bool("progress", false, "")
 	comparePtr      = flag.String("compare", "", "")
 	trimPathPtr     = flag.String("trimpath", "", "")
+	treePtr         = flag.Bool("tree", false, "")
+	maxLineSizePtr  = flag.Int("max-line-size", 0, "")
+	panicRawPtr     = flag.Bool("panic-raw", false, "")
+	coverProfilePtr = flag.String("coverprofile", "", "")
+	groupSkipsPtr   = flag.Bool("group-skips", false, "")
+	timelinePtr     = flag.Bool("timeline", false, "")
+	groupAttrPtr    = flag.String("group-attr", "", "")
 	// Undocumented flags
 	followVerbosePtr = flag.Bool("follow-verbose", false, "")
 	includeTimestamp = flag.Bool("include-timestamp", false, "include timestamps in follow output")
@@ -43,10 +50,22 @@ var (
 	noBordersPtr = flag.Bool("noborders", false, "")
 )
 
+// stringsFlag is a flag that may be set more than once.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
 var usage = `Usage:
     go test ./... -json | tparse [options...]
     go test [packages...] -json | tparse [options...]
     go test [packages...] -json > pkgs.out ; tparse [options...] -file pkgs.out
+    tparse [options...] -file shard1.out -file shard2.out
+    tparse [options...] -file 'shards/*.out'
 
 Options:
     -h                 Show help.
@@ -56,17 +75,35 @@ Options:
     -skip              Display table for skipped tests.
     -notests           Display packages containing no test files or empty test files.
     -smallscreen       Split subtest names vertically to fit on smaller screens.
+    -tree              Display subtests indented under their parent test.
+    -group-skips       Display skipped tests grouped by the reason they were skipped for.
+    -attr              Only display tests with the given attribute, set with t.Attr, e.g., -attr owner=alice.
+                       Repeat the flag to require more than one attribute.
+    -group-attr        Display the number of tests by status for each value of an attribute, e.g., owner.
+    -timeline          Display when each test ran, how long parallel tests were paused waiting to
+                       run, and the peak number of tests running at once in each package.
+    -panic-raw         Display the full goroutine dump of a panic, instead of a condensed trace.
     -slow              Number of slowest tests to display. Default is 0, display all.
     -sort              Sort table output by attribute [name, elapsed, cover]. Default is name.
     -nocolor           Disable all colors. (NO_COLOR also supported)
-    -format            The output format for tables [basic, plain, markdown]. Default is basic.
-    -file              Read test output from a file.
+    -format            The output format for tables [basic, plain, markdown, json]. Default is basic.
+                       The json format is a versioned summary for other tools, instead of tables.
+                       With json, -progress and -follow output is written to stderr.
+    -file              Read test output from a file. Repeat the flag or use a glob pattern to merge
+                       multiple files, e.g., the output of sharded CI runs, into one summary.
+                       Files compressed with gzip or zstd are detected automatically. Quote the glob
+                       pattern, or put the other flags first if the shell expands it.
     -follow            Follow raw output from go test to stdout.
     -follow-output     Write raw output from go test to a file (takes precedence over -follow).
+                       Compressed with gzip if the file name ends in .gz.
     -include-timestamp Include timestamps in follow output. 
     -progress          Print a single summary line for each package. Useful for long running test suites.
-    -compare           Compare against a previous test output file. (experimental)
+    -compare           Compare against a previous test output file, or -format json output. (experimental)
+    -coverprofile      Read a coverage profile written by go test -coverprofile, and display the
+                       coverage by package, file and function, the least covered first. With
+                       -format json, the coverage is part of the report instead.
     -trimpath          Remove path prefix from package names in output, simplifying their display.
+    -max-line-size     Maximum size in bytes of a single output line, longer lines are truncated. Default is 1MiB.
 `
 
 var version string
@@ -76,7 +113,26 @@ func main() {
 	flag.Usage = func() {
 		fmt.Fprint(flag.CommandLine.Output(), usage)
 	}
+	var fileNames stringsFlag
+	flag.Var(&fileNames, "file", "")
+	var attrFlags stringsFlag
+	flag.Var(&attrFlags, "attr", "")
 	flag.Parse()
+	files := []string(fileNames)
+	if len(files) > 0 {
+		// Also accept the remaining arguments as files, such as a glob expanded by the shell:
+		// tparse -file shards/*.out
+		//
+		// Flag parsing stops at the first of these files, so flags that follow are left over as
+		// well. Report them instead of reading them as files.
+		for _, arg := range flag.Args() {
+			if strings.HasPrefix(arg, "-") {
+				fmt.Fprintf(os.Stderr, "invalid option:%q. Flags must come before the files of -file, or quote the glob pattern\n", arg)
+				return
+			}
+		}
+		files = append(files, flag.Args()...)
+	}
 
 	if *vPtr || *versionPtr {
 		if info, ok := debug.ReadBuildInfo(); ok {
@@ -97,6 +153,8 @@ func main() {
 		format = app.OutputFormatPlain
 	case "markdown":
 		format = app.OutputFormatMarkdown
+	case "json":
+		format = app.OutputFormatJSON
 	case "":
 		// This was an existing flag, let's try to avoid breaking users.
 		format = app.OutputFormatBasic
@@ -104,7 +162,7 @@ func main() {
 			format = app.OutputFormatPlain
 		}
 	default:
-		fmt.Fprintf(os.Stderr, "invalid option:%q. The -format flag must be one of: basic, plain or markdown\n", *formatPtr)
+		fmt.Fprintf(os.Stderr, "invalid option:%q. The -format flag must be one of: basic, plain, markdown or json\n", *formatPtr)
 		return
 	}
 	var sorter parse.PackageSorter
@@ -119,6 +177,18 @@ func main() {
 		fmt.Fprintf(os.Stderr, "invalid option:%q. The -sort flag must be one of: name, elapsed or cover\n", *sortPtr)
 		return
 	}
+	var attrs map[string]string
+	for _, attr := range attrFlags {
+		key, value, ok := strings.Cut(attr, "=")
+		if !ok || key == "" {
+			fmt.Fprintf(os.Stderr, "invalid option:%q. The -attr flag must be of the form key=value\n", attr)
+			return
+		}
+		if attrs == nil {
+			attrs = make(map[string]string)
+		}
+		attrs[key] = value
+	}
 
 	if *allPtr {
 		*passPtr = true
@@ -134,7 +204,7 @@ func main() {
 	switch {
 	case *followOutputPtr != "":
 		var err error
-		followOutput, err = os.Create(*followOutputPtr)
+		followOutput, err = utils.CreateFile(*followOutputPtr)
 		if err != nil {
 			fmt.Fprintln(os.Stderr, err)
 			return
@@ -142,6 +212,10 @@ func main() {
 		*followPtr = true
 	case *followPtr, *followVerbosePtr:
 		followOutput = os.Stdout
+		if format == app.OutputFormatJSON {
+			// Keep stdout valid JSON, the report is written to it.
+			followOutput = utils.WriteNopCloser{Writer: os.Stderr}
+		}
 	default:
 		// If no follow flags are set, we should not write to followOutput.
 		followOutput = utils.WriteNopCloser{Writer: io.Discard}
@@ -153,25 +227,49 @@ func main() {
 		FollowOutput:        *followPtr,
 		FollowOutputWriter:  followOutput,
 		FollowOutputVerbose: *followVerbosePtr,
-		FileName:            *fileNamePtr,
+		FileNames:           files,
 		TestTableOptions: app.TestTableOptions{
-			Pass:     *passPtr,
-			Skip:     *skipPtr,
+			Pass:       *passPtr,
+			Skip:       *skipPtr,
+			Trim:       *smallScreenPtr,
+			TrimPath:   *trimPathPtr,
+			Slow:       *slowPtr,
+			Tree:       *treePtr,
+			GroupSkips: *groupSkipsPtr,
+			Attrs:      attrs,
+			GroupAttr:  *groupAttrPtr,
+		},
+		SummaryTableOptions: app.SummaryTableOptions{
 			Trim:     *smallScreenPtr,
 			TrimPath: *trimPathPtr,
-			Slow:     *slowPtr,
 		},
-		SummaryTableOptions: app.SummaryTableOptions{
+		BenchmarkTableOptions: app.BenchmarkTableOptions{
+			Trim:     *smallScreenPtr,
+			TrimPath: *trimPathPtr,
+		},
+		CoverageTableOptions: app.CoverageTableOptions{
 			Trim:     *smallScreenPtr,
 			TrimPath: *trimPathPtr,
 		},
+		TimelineTableOptions: app.TimelineTableOptions{
+			Trim:     *smallScreenPtr,
+			TrimPath: *trimPathPtr,
+		},
+		FailedOptions: app.FailedOptions{
+			Tree:     *treePtr,
+			RawPanic: *panicRawPtr,
+			Attrs:    attrs,
+		},
 		Format:           format,
 		Sorter:           sorter,
 		ShowNoTests:      *showNoTestsPtr,
 		Progress:         *progressPtr,
 		ProgressOutput:   os.Stdout,
 		Compare:          *comparePtr,
+		CoverProfile:     *coverProfilePtr,
+		Timeline:         *timelinePtr,
 		IncludeTimestamp: *includeTimestamp,
+		MaxLineSize:      *maxLineSizePtr,
 
 		// Do not expose publicly.
 		DisableTableOutput: false,
@@ -0,0 +1,170 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Assertion is a failed assertion reported by github.com/stretchr/testify, or a diff produced by
+// github.com/google/go-cmp and reported by the test. testify reports a failed assertion as:
+//
+//	foo_test.go:12:
+//	    	Error Trace:	/home/user/foo/foo_test.go:12
+//	    	Error:      	Not equal:
+//	    	            	expected: 1
+//	    	            	actual  : 2
+//	    	Test:       	TestFoo
+//	    	Messages:   	optional message
+//
+// go-cmp diffs are recognized by the "(-want +got)" convention of the message that precedes them:
+//
+//	foo_test.go:20: user mismatch (-want +got):
+//	      main.User{
+//	    - 	Name: "alice",
+//	    + 	Name: "bob",
+//	      }
+type Assertion struct {
+	// File and Line are the location of the failed assertion, as reported by the testing package.
+	// File is the base name of the file, e.g., "foo_test.go".
+	File string
+	Line int
+	// Trace is the call stack reported by testify in "Error Trace", if any.
+	Trace []string
+	// Message describes the failure, e.g., "Not equal:" or "Should be true". It may span multiple
+	// lines, e.g., "Received unexpected error:" followed by the error.
+	Message string
+	// Messages is the optional message passed to the testify assertion.
+	Messages string
+	// Expected and Actual are the values compared by testify, if reported.
+	Expected string
+	Actual   string
+	// Diff is the line diff from the expected to the actual value, if reported. Removed lines are
+	// expected, added lines are actual. The diff is reported as-is, so for go-cmp diffs with a
+	// "(-got +want)" message the meaning is reversed.
+	Diff []DiffLine
+
+	// Events are the output events the assertion was parsed from.
+	Events []*Event
+}
+
+var (
+	// A testify field, e.g., "\tError:      \tNot equal: ", or a continuation line with no label.
+	testifyFieldRe = regexp.MustCompile(`^\s*\t([A-Za-z ]*?):?\s*\t(.*)$`)
+	// The go-cmp convention for naming the sides of a diff, e.g., "(-want +got)".
+	cmpDiffRe = regexp.MustCompile(`\(-\w+ \+\w+\):?\s*$`)
+)
+
+// parseAssertions extracts testify and go-cmp assertions from the failures of a failed test.
+func parseAssertions(failures []*Failure) []*Assertion {
+	var assertions []*Assertion
+	for _, f := range failures {
+		message, lines := strings.TrimSpace(f.lines[0]), f.lines[1:]
+		var a *Assertion
+		switch {
+		case message == "" && len(lines) > 0 && strings.HasPrefix(lines[0], "\tError Trace:"):
+			a = parseTestify(lines)
+		case cmpDiffRe.MatchString(message) && len(lines) > 0:
+			a = &Assertion{
+				Message: message,
+				Diff:    parseCmpDiff(lines),
+			}
+		}
+		if a == nil {
+			continue
+		}
+		a.File, a.Line, a.Events = f.File, f.Line, f.Events
+		assertions = append(assertions, a)
+	}
+	return assertions
+}
+
+// parseTestify parses the fields of a testify failure, without the location line.
+func parseTestify(lines []string) *Assertion {
+	a := new(Assertion)
+	fields := make(map[string][]string)
+	var label string
+	for _, line := range lines {
+		ss := testifyFieldRe.FindStringSubmatch(line)
+		if ss == nil {
+			continue
+		}
+		if ss[1] != "" {
+			label = ss[1]
+		}
+		fields[label] = append(fields[label], ss[2])
+	}
+	for _, trace := range fields["Error Trace"] {
+		if trace = strings.TrimSpace(trace); trace != "" {
+			a.Trace = append(a.Trace, trace)
+		}
+	}
+	a.Messages = strings.Join(fields["Messages"], "\n")
+
+	errorLines := fields["Error"]
+	if len(errorLines) == 0 {
+		return nil
+	}
+	a.Message = strings.TrimSpace(errorLines[0])
+	var inDiff bool
+	for _, line := range errorLines[1:] {
+		switch {
+		case inDiff:
+			if strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") || strings.HasPrefix(line, "@@ ") {
+				continue
+			}
+			a.Diff = append(a.Diff, unifiedDiffLine(line))
+		case line == "Diff:":
+			inDiff = true
+		case strings.HasPrefix(line, "expected: "):
+			a.Expected = strings.TrimPrefix(line, "expected: ")
+		case strings.HasPrefix(line, "actual  : "):
+			a.Actual = strings.TrimPrefix(line, "actual  : ")
+		default:
+			// E.g., the error reported by assert.NoError.
+			a.Message += "\n" + strings.TrimRight(line, " ")
+		}
+	}
+	return a
+}
+
+// unifiedDiffLine parses a line of a unified diff, which has a single character prefix.
+func unifiedDiffLine(line string) DiffLine {
+	switch {
+	case strings.HasPrefix(line, "-"):
+		return DiffLine{Op: DiffRemoved, Text: line[1:]}
+	case strings.HasPrefix(line, "+"):
+		return DiffLine{Op: DiffAdded, Text: line[1:]}
+	}
+	return DiffLine{Op: DiffEqual, Text: strings.TrimPrefix(line, " ")}
+}
+
+// parseCmpDiff parses a go-cmp diff, where each line is prefixed by "-", "+" or a space, and a
+// separator. go-cmp deliberately randomizes its spaces between a regular and a non-breaking space,
+// to discourage parsing its output, so both are accepted.
+func parseCmpDiff(lines []string) []DiffLine {
+	diff := make([]DiffLine, 0, len(lines))
+	for _, line := range lines {
+		var op DiffOp
+		switch {
+		case strings.HasPrefix(line, "-"):
+			op = DiffRemoved
+		case strings.HasPrefix(line, "+"):
+			op = DiffAdded
+		}
+		if op != DiffEqual {
+			line = line[1:]
+		} else {
+			line = trimCmpSpace(line)
+		}
+		diff = append(diff, DiffLine{Op: op, Text: trimCmpSpace(line)})
+	}
+	return diff
+}
+
+// trimCmpSpace removes a single leading space or non-breaking space.
+func trimCmpSpace(s string) string {
+	if strings.HasPrefix(s, " ") {
+		return s[1:]
+	}
+	return strings.TrimPrefix(s, "\u00a0")
+}
@@ -0,0 +1,31 @@
+package parse
+
+// addAttrEvent records an attribute set with t.Attr, or the artifact directory of the test.
+func addAttrEvent(t *Test, e *Event) {
+	switch e.Action {
+	case ActionAttr:
+		if t.Attrs == nil {
+			t.Attrs = make(map[string]string)
+		}
+		// Same as go test, the last value set wins.
+		t.Attrs[e.Key] = e.Value
+	case ActionArtifacts:
+		// Each attempt of a test run with go test -count=N has its own directory.
+		if e.Path != "" {
+			t.Artifacts = append(t.Artifacts, e.Path)
+		}
+	}
+}
+
+// Attr returns the value of the attribute with the given key, e.g., "alice" for
+// t.Attr("owner", "alice"), and whether it was set. A subtest that did not set the attribute
+// inherits it from the nearest parent test that did, so attributes such as an owner or a suite
+// only need to be set on the top-level test.
+func (t *Test) Attr(key string) (string, bool) {
+	for ; t != nil; t = t.Parent {
+		if value, ok := t.Attrs[key]; ok {
+			return value, true
+		}
+	}
+	return "", false
+}
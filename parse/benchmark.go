@@ -0,0 +1,92 @@
+package parse
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Benchmark is a single benchmark result line reported by go test -bench. For example:
+//
+//	BenchmarkFoo-8   1000   1234 ns/op   56 B/op   2 allocs/op
+//
+// A benchmark run with -count=N or -cpu=a,b reports one result per run.
+type Benchmark struct {
+	// Name is the benchmark name without the GOMAXPROCS suffix, e.g., BenchmarkFoo/case.
+	Name    string
+	Package string
+	// Procs is the GOMAXPROCS value the benchmark ran with, taken from the name suffix, e.g., 8 for
+	// BenchmarkFoo-8. Zero if the suffix is absent.
+	Procs int
+
+	Iterations int64
+	NsPerOp    float64
+	// MBPerSec is only reported when the benchmark calls b.SetBytes.
+	MBPerSec float64
+	// BytesPerOp and AllocsPerOp are only reported with -benchmem or b.ReportAllocs, see HasMem.
+	BytesPerOp  float64
+	AllocsPerOp float64
+	HasMem      bool
+
+	// Metrics holds custom metrics reported with b.ReportMetric, keyed by unit, e.g., "widgets/op".
+	Metrics map[string]float64
+}
+
+// FullName returns the benchmark name as printed by go test, including the GOMAXPROCS suffix.
+func (b *Benchmark) FullName() string {
+	if b.Procs == 0 {
+		return b.Name
+	}
+	return b.Name + "-" + strconv.Itoa(b.Procs)
+}
+
+// parseBenchmarkLine parses a single benchmark result line. It reports false if the line is not a
+// benchmark result, e.g., a bare "BenchmarkFoo" line printed before the benchmark starts.
+func parseBenchmarkLine(line string) (*Benchmark, bool) {
+	fields := strings.Fields(line)
+	// At minimum: name, iterations, value and unit.
+	if len(fields) < 4 || len(fields)%2 != 0 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return nil, false
+	}
+	iterations, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	b := &Benchmark{
+		Name:       fields[0],
+		Iterations: iterations,
+	}
+	if i := strings.LastIndex(b.Name, "-"); i > 0 {
+		if procs, err := strconv.Atoi(b.Name[i+1:]); err == nil {
+			b.Name, b.Procs = b.Name[:i], procs
+		}
+	}
+	for i := 2; i < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, false
+		}
+		switch unit := fields[i+1]; unit {
+		case "ns/op":
+			b.NsPerOp = value
+		case "MB/s":
+			b.MBPerSec = value
+		case "B/op":
+			b.BytesPerOp = value
+			b.HasMem = true
+		case "allocs/op":
+			b.AllocsPerOp = value
+			b.HasMem = true
+		default:
+			if b.Metrics == nil {
+				b.Metrics = make(map[string]float64)
+			}
+			b.Metrics[unit] = value
+		}
+	}
+	return b, true
+}
+
+// isBenchmark reports whether the test name belongs to a benchmark.
+func isBenchmark(name string) bool {
+	return strings.HasPrefix(name, "Benchmark")
+}
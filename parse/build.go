@@ -0,0 +1,127 @@
+package parse
+
+import (
+	"strings"
+)
+
+// BuildEvent is a single event emitted by the go command while building a test binary. Since
+// go1.24, go test -json reports build output as JSON, interleaved with test events:
+//
+//	{"ImportPath":"example.com/foo [example.com/foo.test]","Action":"build-output","Output":"..."}
+//	{"ImportPath":"example.com/foo [example.com/foo.test]","Action":"build-fail"}
+type BuildEvent struct {
+	ImportPath string
+	Action     Action // build-output or build-fail
+	Output     string
+}
+
+// BuildEvent returns the event as a BuildEvent. It reports false if the event is not a build
+// event, i.e., it does not have an ImportPath.
+func (e *Event) BuildEvent() (*BuildEvent, bool) {
+	if e.ImportPath == "" {
+		return nil, false
+	}
+	return &BuildEvent{
+		ImportPath: e.ImportPath,
+		Action:     e.Action,
+		Output:     e.Output,
+	}, true
+}
+
+// Build groups the build events of a single package build, identified by its ImportPath. Note,
+// the ImportPath is a package ID and may include the test variant, e.g.,
+// "example.com/foo [example.com/foo.test]".
+type Build struct {
+	ImportPath string
+	Events     []*BuildEvent
+	// Failed reports whether the build failed. Build output is not always an error, e.g., the
+	// linker may print warnings for a build that succeeds.
+	Failed bool
+}
+
+// Output returns the combined output of the build, such as compiler errors.
+func (b *Build) Output() string {
+	var sb strings.Builder
+	for _, e := range b.Events {
+		sb.WriteString(e.Output)
+	}
+	return sb.String()
+}
+
+// Errors returns the lines of build output, excluding the "# package" header lines the go
+// command prints before the output of each package.
+func (b *Build) Errors() []string {
+	var lines []string
+	for _, line := range strings.Split(b.Output(), "\n") {
+		if line == "" || strings.HasPrefix(line, "# ") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// addBuildEvent groups the build event by ImportPath.
+func (s *GoTestSummary) addBuildEvent(e *BuildEvent) {
+	b := s.getBuild(e.ImportPath)
+	b.Events = append(b.Events, e)
+	if e.Action == ActionBuildFail {
+		b.Failed = true
+	}
+}
+
+func (s *GoTestSummary) getBuild(importPath string) *Build {
+	if s.Builds == nil {
+		s.Builds = make(map[string]*Build)
+	}
+	b, ok := s.Builds[importPath]
+	if !ok {
+		b = &Build{ImportPath: importPath}
+		s.Builds[importPath] = b
+	}
+	return b
+}
+
+// addRawBuildOutput collects build output printed as plain text, before go1.24 or with
+// GODEBUG=gotestjsonbuildtext=1. The output of each package starts with a "# package" header:
+//
+//	# example.com/foo [example.com/foo.test]
+//	./foo_test.go:6:2: undefined: hello
+//	FAIL	example.com/foo [build failed]
+//
+// Lines are only kept if they're followed by the [build failed] line of the package, because
+// arbitrary text may precede the JSON output.
+func (s *GoTestSummary) addRawBuildOutput(str string) {
+	if strings.HasPrefix(str, "# ") {
+		s.rawBuild = &Build{ImportPath: strings.TrimPrefix(str, "# ")}
+	}
+	if s.rawBuild != nil {
+		s.rawBuild.Events = append(s.rawBuild.Events, &BuildEvent{
+			ImportPath: s.rawBuild.ImportPath,
+			Action:     ActionBuildOutput,
+			Output:     str + "\n",
+		})
+	}
+}
+
+// linkRawBuild links the pending plain text build output to the package that failed to build.
+func (s *GoTestSummary) linkRawBuild(pkgName string, pkg *Package) {
+	b := s.rawBuild
+	s.rawBuild = nil
+	if b == nil || buildPackage(b.ImportPath) != pkgName {
+		return
+	}
+	build := s.getBuild(b.ImportPath)
+	if len(build.Events) == 0 {
+		build.Events = b.Events
+	}
+	build.Failed = true
+	pkg.FailedBuild = build
+}
+
+// buildPackage returns the package path of a package ID, e.g., "example.com/foo" for
+// "example.com/foo [example.com/foo.test]".
+func buildPackage(importPath string) string {
+	before, _, _ := strings.Cut(importPath, " ")
+	return before
+}
@@ -0,0 +1,199 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Plain text go test output, i.e., go test run without -json, is converted to events in the same
+// way cmd/test2json does. For example, the output of go test -v:
+//
+//	=== RUN   TestFoo
+//	    foo_test.go:10: something went wrong
+//	--- FAIL: TestFoo (0.00s)
+//	FAIL
+//	FAIL	example.com/foo	0.004s
+//
+// Unlike test2json, the converter has no access to the go command and does not know which
+// package is running until the final "ok" or "FAIL" line of the package. Events are held back
+// until then, and the events have no time.
+var (
+	textResultRe  = regexp.MustCompile(`^(\s*)--- (PASS|FAIL|SKIP|BENCH): (\S+)(?: \(([0-9.]+)s\))?`)
+	textPackageRe = regexp.MustCompile(`^(ok|FAIL|\?)\s+(\S+)\t(?:([0-9.]+)s|\(cached\)|\[no test files\])`)
+)
+
+// isTextOutput reports whether the line looks like plain text go test output, such as a "=== RUN"
+// line, a package result line, or the "-test.shuffle" line printed before the tests of a package
+// run with go test -shuffle.
+func isTextOutput(line string) bool {
+	if failedBuildOrSetupRe.MatchString(line) {
+		// Also printed as plain text when running go test with -json, before go1.24.
+		return false
+	}
+	return strings.HasPrefix(line, updatePrefixRun) ||
+		shuffleRe.MatchString(line) ||
+		textResultRe.MatchString(line) ||
+		textPackageRe.MatchString(line)
+}
+
+// textConverter converts plain text go test output to events, one line at a time.
+type textConverter struct {
+	// test is the name of the test that output is attributed to, or empty for package output.
+	test string
+	// result is the result event of the reported test. It's held back until the end of the report,
+	// so that the output of the report precedes it, the same as with go test -json.
+	result *Event
+	// build is true after a "# package" header, until the end of the build output.
+	build bool
+	// pending holds the events of the current package.
+	pending []*Event
+}
+
+// convert converts a single line of output. The events of a package are returned once the
+// package has finished. Lines that are not test output, such as build errors, are reported as raw
+// and produce no events.
+func (c *textConverter) convert(line string) (events []*Event, raw bool) {
+	switch {
+	case strings.HasPrefix(line, "# "):
+		c.build = true
+		return nil, true
+	case failedBuildOrSetupRe.MatchString(line):
+		c.build = false
+		return nil, true
+	}
+	output := line + "\n"
+	if ss := textPackageRe.FindStringSubmatch(line); ss != nil {
+		c.build = false
+		c.endReport()
+		return c.finish(ss[1], ss[2], ss[3], output), false
+	}
+	if action, name, ok := textUpdate(line); ok {
+		c.build = false
+		c.endReport()
+		e := &Event{Action: action, Test: name}
+		// The test name is followed by the path of the artifacts, or the key and value of the
+		// attribute. Same as go test -json, the value may contain spaces, but the key may not.
+		switch action {
+		case ActionArtifacts:
+			e.Test, e.Path, _ = strings.Cut(name, " ")
+		case ActionAttr:
+			var rest string
+			e.Test, rest, _ = strings.Cut(name, " ")
+			e.Key, e.Value, _ = strings.Cut(rest, " ")
+		}
+		c.test = e.Test
+		// Same as go test -json, "=== NAME" lines only switch the test that output is attributed
+		// to.
+		if action != "" {
+			c.add(e)
+			c.add(&Event{Action: ActionOutput, Test: e.Test, Output: output})
+		}
+		return nil, false
+	}
+	if ss := textResultRe.FindStringSubmatch(line); ss != nil {
+		c.build = false
+		c.endReport()
+		name := ss[3]
+		c.test = name
+		elapsed, _ := strconv.ParseFloat(ss[4], 64)
+		c.add(&Event{Action: ActionOutput, Test: name, Output: output})
+		c.result = &Event{Action: textResultAction(ss[2]), Test: name, Elapsed: elapsed}
+		return nil, false
+	}
+	if c.build {
+		return nil, true
+	}
+	// Same as go test -json, output belongs to the last reported test, e.g., the got and want
+	// output of a failed Example, until the final "PASS" or "FAIL" line of the package.
+	if line == "PASS" || line == "FAIL" {
+		c.endReport()
+		c.test = ""
+	}
+	c.add(&Event{Action: ActionOutput, Test: c.test, Output: output})
+	return nil, false
+}
+
+// flush returns the events of tests of a package that did not finish, e.g., because the output
+// was cut short. The package is unknown. Output that does not belong to a test, such as the final
+// "FAIL" line printed by go test, is discarded.
+func (c *textConverter) flush() []*Event {
+	c.endReport()
+	var events []*Event
+	for _, e := range c.pending {
+		if e.Test != "" {
+			events = append(events, e)
+		}
+	}
+	c.pending = nil
+	return events
+}
+
+func (c *textConverter) add(e *Event) {
+	c.pending = append(c.pending, e)
+}
+
+// endReport ends the report of a "--- PASS" or "--- FAIL" line, if any, and adds the held back
+// result event.
+func (c *textConverter) endReport() {
+	if c.result != nil {
+		c.add(c.result)
+		c.result = nil
+	}
+}
+
+// finish adds the package result and returns the events of the package.
+func (c *textConverter) finish(result, pkg, elapsed, output string) []*Event {
+	action := ActionPass
+	switch result {
+	case "FAIL":
+		action = ActionFail
+	case "?":
+		action = ActionSkip
+	}
+	e := &Event{Action: action}
+	e.Elapsed, _ = strconv.ParseFloat(elapsed, 64)
+	c.add(&Event{Action: ActionOutput, Output: output})
+	c.add(e)
+
+	events := c.pending
+	for _, e := range events {
+		e.Package = pkg
+	}
+	c.pending = nil
+	c.test = ""
+	return events
+}
+
+// textUpdate parses a "=== RUN" line, or one of the other update lines. The action is empty for
+// update lines that have no corresponding event, such as "=== NAME".
+func textUpdate(line string) (action Action, name string, ok bool) {
+	for _, u := range []struct {
+		prefix string
+		action Action
+	}{
+		{updatePrefixRun, ActionRun},
+		{updatePrefixPause, ActionPause},
+		{updatePrefixCont, ActionCont},
+		{updatePrefixName, ""},
+		{updatePrefixAttr, ActionAttr},
+		{updatePrefixArtifacts, ActionArtifacts},
+	} {
+		if strings.HasPrefix(line, u.prefix) {
+			return u.action, strings.TrimSpace(strings.TrimPrefix(line, u.prefix)), true
+		}
+	}
+	return "", "", false
+}
+
+func textResultAction(result string) Action {
+	switch result {
+	case "PASS":
+		return ActionPass
+	case "SKIP":
+		return ActionSkip
+	case "BENCH":
+		return ActionBench
+	}
+	return ActionFail
+}
@@ -0,0 +1,252 @@
+package parse
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoverProfile is a coverage profile written by go test -coverprofile, in the format read by go
+// tool cover:
+//
+//	mode: set
+//	example.com/foo/foo.go:8.2,9.16 2 1
+//	example.com/foo/foo.go:10.3,12.1 1 0
+//
+// Each line after the mode is a block of statements: the file, the start and end of the block as
+// line.column, the number of statements and how often the block ran.
+type CoverProfile struct {
+	// Mode is the coverage mode: set, count or atomic.
+	Mode string
+	// Files holds the coverage of each file, sorted by name.
+	Files []*FileCoverage
+}
+
+// FileCoverage is the coverage of a single source file.
+type FileCoverage struct {
+	// Name is the import path of the package followed by the base name of the file, e.g.,
+	// "example.com/foo/foo.go".
+	Name string
+	// Blocks holds the blocks of statements in the file, in the order they appear in the file.
+	Blocks []*CoverBlock
+}
+
+// CoverBlock is a block of statements that either all ran or did not run.
+type CoverBlock struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+	// NumStmt is the number of statements in the block.
+	NumStmt int
+	// Count is how often the block ran. In set mode it is either 0 or 1.
+	Count int
+}
+
+// Coverage is the number of statements and how many of them ran at least once.
+type Coverage struct {
+	Statements int
+	Covered    int
+}
+
+// FuncCoverage is the coverage of a single function.
+type FuncCoverage struct {
+	// Name is the name of the function. Methods are prefixed with the receiver type, e.g.,
+	// "(*Stack).Pop" or "Stack.Len".
+	Name string
+	// Line is the line the function is declared on.
+	Line int
+	Coverage
+}
+
+// Percent returns the percentage of statements covered, or 0 if there are no statements.
+func (c Coverage) Percent() float64 {
+	if c.Statements == 0 {
+		return 0
+	}
+	return float64(c.Covered) / float64(c.Statements) * 100
+}
+
+func (c *Coverage) add(b *CoverBlock) {
+	c.Statements += b.NumStmt
+	if b.Count > 0 {
+		c.Covered += b.NumStmt
+	}
+}
+
+var coverBlockRe = regexp.MustCompile(`^(.+):(\d+)\.(\d+),(\d+)\.(\d+) (\d+) (\d+)$`)
+
+// ParseCoverProfile parses a coverage profile. Profiles that were concatenated, e.g., from
+// separate go test runs, are supported as long as they use the same mode. Blocks reported more
+// than once, e.g., when run with -coverpkg, are combined: in set mode a block is covered if any of
+// its reports is, otherwise the counts are added up.
+func ParseCoverProfile(r io.Reader) (*CoverProfile, error) {
+	p := new(CoverProfile)
+	files := make(map[string]*FileCoverage)
+	blocks := make(map[string]*CoverBlock)
+	sc := bufio.NewScanner(r)
+	var n int
+	for sc.Scan() {
+		n++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		if mode, ok := strings.CutPrefix(line, "mode: "); ok {
+			if p.Mode != "" && p.Mode != mode {
+				return nil, fmt.Errorf("line %d: mode %q does not match mode %q", n, mode, p.Mode)
+			}
+			p.Mode = mode
+			continue
+		}
+		if p.Mode == "" {
+			return nil, fmt.Errorf("line %d: missing mode line", n)
+		}
+		ss := coverBlockRe.FindStringSubmatch(line)
+		if ss == nil {
+			return nil, fmt.Errorf("line %d: invalid coverage block: %q", n, line)
+		}
+		b := new(CoverBlock)
+		for i, v := range []*int{&b.StartLine, &b.StartCol, &b.EndLine, &b.EndCol, &b.NumStmt, &b.Count} {
+			// The expression only matches digits, but the value may still overflow.
+			var err error
+			if *v, err = strconv.Atoi(ss[i+2]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid coverage block: %w", n, err)
+			}
+		}
+		key := ss[1] + ":" + strings.Join(ss[2:6], ",")
+		if same, ok := blocks[key]; ok {
+			if p.Mode == "set" {
+				same.Count = max(same.Count, b.Count)
+			} else {
+				same.Count += b.Count
+			}
+			continue
+		}
+		blocks[key] = b
+		f, ok := files[ss[1]]
+		if !ok {
+			f = &FileCoverage{Name: ss[1]}
+			files[ss[1]] = f
+			p.Files = append(p.Files, f)
+		}
+		f.Blocks = append(f.Blocks, b)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(p.Files, func(i, j int) bool {
+		return p.Files[i].Name < p.Files[j].Name
+	})
+	for _, f := range p.Files {
+		sort.SliceStable(f.Blocks, func(i, j int) bool {
+			bi, bj := f.Blocks[i], f.Blocks[j]
+			return bi.StartLine < bj.StartLine || (bi.StartLine == bj.StartLine && bi.StartCol < bj.StartCol)
+		})
+	}
+	return p, nil
+}
+
+// Package returns the import path of the package the file belongs to.
+func (f *FileCoverage) Package() string {
+	return path.Dir(f.Name)
+}
+
+// Coverage returns the statement coverage of the file.
+func (f *FileCoverage) Coverage() Coverage {
+	var c Coverage
+	for _, b := range f.Blocks {
+		c.add(b)
+	}
+	return c
+}
+
+// Functions returns the coverage of the functions declared in the file, in the order they are
+// declared. The functions are found by parsing src, the source of the file. Statements of function
+// literals count towards the function they are declared in.
+func (f *FileCoverage) Functions(src []byte) ([]*FuncCoverage, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, f.Name, src, 0)
+	if err != nil {
+		return nil, err
+	}
+	var funcs []*FuncCoverage
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+		start, end := fset.Position(fn.Pos()), fset.Position(fn.End())
+		fc := &FuncCoverage{Name: funcName(fn), Line: start.Line}
+		for _, b := range f.Blocks {
+			if after(b.StartLine, b.StartCol, start) && !after(b.EndLine, b.EndCol, end) {
+				fc.add(b)
+			}
+		}
+		funcs = append(funcs, fc)
+	}
+	return funcs, nil
+}
+
+// after reports whether line.col is at or after pos.
+func after(line, col int, pos token.Position) bool {
+	return line > pos.Line || (line == pos.Line && col >= pos.Column)
+}
+
+// funcName returns the name of a function, prefixed with the receiver type for methods.
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	var ptr string
+	if star, ok := typ.(*ast.StarExpr); ok {
+		ptr = "*"
+		typ = star.X
+	}
+	// Drop the type parameters of generic receivers, e.g., List[T].
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	name := fn.Name.Name
+	if ident, ok := typ.(*ast.Ident); ok {
+		if ptr != "" {
+			return "(" + ptr + ident.Name + ")." + name
+		}
+		return ident.Name + "." + name
+	}
+	return name
+}
+
+// Packages returns the statement coverage of each package in the profile, by import path.
+func (p *CoverProfile) Packages() map[string]Coverage {
+	packages := make(map[string]Coverage)
+	for _, f := range p.Files {
+		c := packages[f.Package()]
+		for _, b := range f.Blocks {
+			c.add(b)
+		}
+		packages[f.Package()] = c
+	}
+	return packages
+}
+
+// Total returns the statement coverage of all files in the profile.
+func (p *CoverProfile) Total() Coverage {
+	var c Coverage
+	for _, f := range p.Files {
+		for _, b := range f.Blocks {
+			c.add(b)
+		}
+	}
+	return c
+}
@@ -27,6 +27,12 @@ type Event struct {
 	//  Added in go1.24:
 	//   - build-fail
 	//   - build-output
+	//
+	//  Added in go1.25:
+	//   - attr
+	//
+	//  Added in go1.26:
+	//   - artifacts
 	Action Action
 
 	// Portion of the test's output (standard output and standard error merged together)
@@ -53,6 +59,21 @@ type Event struct {
 	// will be reported in the final "fail" event's FailedBuild field.
 	FailedBuild string
 
+	// OutputType, if present, classifies the output of an output event. Added in go1.25:
+	//   - frame: a line printed by the test framework, e.g., "=== RUN" or "--- FAIL"
+	//   - error: the first line of a test error, e.g., reported by t.Error or t.Fatal
+	//   - error-continue: a continuation line of a test error
+	OutputType string
+
+	// Key and Value are the name and value of an attribute set with t.Attr, reported by an attr
+	// event. Added in go1.25.
+	Key   string
+	Value string
+
+	// Path is the directory of the test artifacts, see t.ArtifactDir, reported by an artifacts
+	// event. Added in go1.26.
+	Path string
+
 	// BuildEvent specific fields.
 	//
 	// TODO(mf): Unfortunately the output has both BuildEvent and TestEvent interleaved in the
@@ -92,6 +113,8 @@ func NewEvent(data []byte) (*Event, error) {
 // === RUN
 // === PAUSE
 // === CONT
+// === ATTR
+// === ARTIFACTS
 // If output is none one of the above return false.
 func (e *Event) DiscardOutput() bool {
 	for i := range updates {
@@ -117,12 +140,17 @@ const (
 	updatePrefixPass  = "=== PASS  "
 	updatePrefixFail  = "=== FAIL  "
 	updatePrefixSkip  = "=== SKIP  "
+
+	updatePrefixAttr      = "=== ATTR  "
+	updatePrefixArtifacts = "=== ARTIFACTS "
 )
 
 var updates = []string{
 	updatePrefixRun,
 	updatePrefixPause,
 	updatePrefixCont,
+	updatePrefixAttr,
+	updatePrefixArtifacts,
 }
 
 // Prefix for the different types of test results. See
@@ -162,6 +190,24 @@ func (e *Event) LastLine() bool {
 	return e.Test == "" && e.Output == "" && (e.Action == ActionPass || e.Action == ActionFail)
 }
 
+// isPackageResult reports whether the event is a line go test prints to report the result of a
+// package, e.g., "PASS", "FAIL", "ok  \tpkg\t0.01s" or "coverage: 80.0% of statements".
+func (e *Event) isPackageResult() bool {
+	if e.OutputType == "frame" {
+		// Since go1.25 these lines are marked as framing, along with the test updates.
+		return true
+	}
+	if e.Output == bigPass+"\n" || e.Output == bigFail+"\n" {
+		return true
+	}
+	for _, prefix := range []string{"ok  \t", "FAIL\t", "?   \t", "coverage: "} {
+		if strings.HasPrefix(e.Output, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // NoTestFiles reports special event case for packages containing no test files:
 // "?   \tpackage\t[no test files]\n"
 func (e *Event) NoTestFiles() bool {
@@ -215,6 +261,10 @@ func (e *Event) IsRace() bool {
 func (e *Event) IsPanic() bool {
 	// Let's see how this goes. If a user has this in one of their output lines, I think it's
 	// defensible to suggest updating their output.
+	if e.IsTimeout() {
+		// Reported separately, see Package.Timeout.
+		return false
+	}
 	if strings.HasPrefix(e.Output, "panic: ") {
 		return true
 	}
@@ -247,8 +297,18 @@ const (
 	// Added in go1.24 to denote a build failure.
 	ActionBuildFail   Action = "build-fail"   // the build failed
 	ActionBuildOutput Action = "build-output" // the toolchain printed output
+
+	// Added in go1.25 to report test attributes.
+	ActionAttr Action = "attr" // the test set an attribute with t.Attr
+
+	// Added in go1.26 to report test artifacts.
+	ActionArtifacts Action = "artifacts" // the test created its artifact directory
 )
 
+// ActionIncomplete is not emitted by go test. It is the status of a test that ran but never
+// reported a pass, fail or skip event, see Test.Status.
+const ActionIncomplete Action = "incomplete"
+
 func (a Action) String() string {
 	return string(a)
 }
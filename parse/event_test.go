@@ -400,3 +400,26 @@ func TestDiscardOutput(t *testing.T) {
 		}
 	}
 }
+
+func TestPackageResult(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		raw  string
+		want bool
+	}{
+		{`{"Action":"output","Package":"fmt","Output":"PASS\n"}`, true},
+		{`{"Action":"output","Package":"fmt","Output":"FAIL\n"}`, true},
+		{`{"Action":"output","Package":"fmt","Output":"ok  \tfmt\t0.096s\n"}`, true},
+		{`{"Action":"output","Package":"fmt","Output":"FAIL\tfmt\t0.003s\n"}`, true},
+		{`{"Action":"output","Package":"fmt","Output":"coverage: 81.2% of statements\n"}`, true},
+		{`{"Action":"output","Package":"fmt","Output":"ok  \tfmt\t0.003s\n","OutputType":"frame"}`, true},
+		{`{"Action":"output","Package":"fmt","Output":"exit status 1\n"}`, false},
+		{`{"Action":"output","Package":"fmt","Output":"2018/10/15 21:03:52 connecting to db\n"}`, false},
+	}
+	for _, tc := range tt {
+		e, err := NewEvent([]byte(tc.raw))
+		require.NoError(t, err)
+		require.Equal(t, tc.want, e.isPackageResult(), e.Output)
+	}
+}
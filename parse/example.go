@@ -0,0 +1,143 @@
+package parse
+
+import (
+	"sort"
+	"strings"
+)
+
+// ExampleFailure holds the output of a failed Example test along with the expected output from
+// its "// Output:" comment. go test reports it as:
+//
+//	--- FAIL: ExampleFoo (0.00s)
+//	got:
+//	hello
+//	want:
+//	hello, world
+type ExampleFailure struct {
+	Got  string
+	Want string
+	// Unordered reports whether the example uses an "// Unordered output:" comment, in which case
+	// the order of the output lines does not matter.
+	Unordered bool
+}
+
+// DiffOp is the kind of change of a single line in a diff.
+type DiffOp int
+
+const (
+	// DiffEqual is a line found in both the expected and actual output.
+	DiffEqual DiffOp = iota
+	// DiffRemoved is a line that was expected but not printed.
+	DiffRemoved
+	// DiffAdded is a line that was printed but not expected.
+	DiffAdded
+)
+
+// DiffLine is a single line of a line-based diff.
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// Diff returns a line diff from the expected (Want) to the actual (Got) output. For unordered
+// examples the lines are sorted first, the same way go test compares them.
+func (e *ExampleFailure) Diff() []DiffLine {
+	want, got := splitLines(e.Want), splitLines(e.Got)
+	if e.Unordered {
+		sort.Strings(want)
+		sort.Strings(got)
+	}
+	return diffLines(want, got)
+}
+
+// isExample reports whether the test name belongs to an Example test.
+func isExample(name string) bool {
+	return strings.HasPrefix(name, "Example")
+}
+
+// parseExampleFailure extracts the got and want sections from the output of a failed Example
+// test. It returns nil if the output does not contain both sections, e.g., the example panicked
+// before its output was compared.
+func parseExampleFailure(events []*Event) *ExampleFailure {
+	var got, want []string
+	var section *[]string
+	var unordered, found bool
+	for _, e := range events {
+		if e.Action != ActionOutput {
+			continue
+		}
+		switch e.Output {
+		case "got:\n":
+			section = &got
+			continue
+		case "want:\n", "want (unordered):\n":
+			if section != &got {
+				return nil
+			}
+			unordered = e.Output == "want (unordered):\n"
+			section = &want
+			found = true
+			continue
+		}
+		if section != nil {
+			*section = append(*section, e.Output)
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &ExampleFailure{
+		Got:       strings.TrimSpace(strings.Join(got, "")),
+		Want:      strings.TrimSpace(strings.Join(want, "")),
+		Unordered: unordered,
+	}
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// diffLines returns a minimal line diff from a to b, based on the longest common subsequence.
+// Example output is small, so the quadratic table is fine.
+func diffLines(a, b []string) []DiffLine {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: DiffRemoved, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffAdded, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: DiffRemoved, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: DiffAdded, Text: b[j]})
+	}
+	return lines
+}
@@ -0,0 +1,97 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Failure is a failure reported by a test with t.Error, t.Fatal or one of their variants. The
+// testing package prefixes the message with the location of the call, and indents it by the
+// nesting level of the (sub)test:
+//
+//	=== RUN   TestFoo/bar
+//	        foo_test.go:42: got 1, want 2
+//	            and some more detail
+//
+// Lines of a multi-line message are indented by 4 more spaces than the location.
+type Failure struct {
+	// File and Line are the location of the failure. File is the base name of the file, e.g.,
+	// "foo_test.go".
+	File string
+	Line int
+	// Message is the failure message without the location and indentation. Lines of a multi-line
+	// message are separated by "\n".
+	Message string
+
+	// Events are the output events the failure was parsed from.
+	Events []*Event
+
+	// lines holds the first line of the message, followed by its continuation lines.
+	lines []string
+}
+
+// locationRe matches the location the testing package prefixes to t.Log and t.Error output, e.g.,
+// "    foo_test.go:12: message".
+var locationRe = regexp.MustCompile(`^(\s*)([\w.\-]+\.go):(\d+): ?(.*)$`)
+
+// parseFailures extracts failures from the output events of a failed test.
+//
+// Before go1.25 the output of t.Log and t.Error can't be told apart, and lines logged by a failed
+// test are reported as failures as well. From go1.25 onwards the output type of the events is used
+// to only report errors.
+func parseFailures(events []*Event) []*Failure {
+	var typed bool
+	for _, e := range events {
+		if e.OutputType != "" {
+			typed = true
+			break
+		}
+	}
+	return parseMessages(events, func(e *Event) bool {
+		return !typed || e.OutputType == "error"
+	})
+}
+
+// parseMessages extracts the messages logged by a test, such as with t.Log or t.Error, from its
+// output events. Only messages that start with an event accepted by include are returned.
+func parseMessages(events []*Event, include func(*Event) bool) []*Failure {
+	var failures []*Failure
+	for i := 0; i < len(events); i++ {
+		e := events[i]
+		if e.Action != ActionOutput || !include(e) {
+			continue
+		}
+		ss := locationRe.FindStringSubmatch(strings.TrimSuffix(e.Output, "\n"))
+		if ss == nil {
+			continue
+		}
+		f := &Failure{
+			File:   ss[2],
+			lines:  []string{ss[4]},
+			Events: []*Event{e},
+		}
+		f.Line, _ = strconv.Atoi(ss[3])
+		indent := ss[1] + "    "
+		j := i + 1
+		for ; j < len(events); j++ {
+			if events[j].Action != ActionOutput {
+				continue
+			}
+			line := strings.TrimSuffix(events[j].Output, "\n")
+			if !strings.HasPrefix(line, indent) {
+				break
+			}
+			f.lines = append(f.lines, strings.TrimPrefix(line, indent))
+			f.Events = append(f.Events, events[j])
+		}
+		f.Message = strings.Join(f.lines, "\n")
+		if f.lines[0] == "" {
+			// E.g., testify starts its message on the line after the location.
+			f.Message = strings.Join(f.lines[1:], "\n")
+		}
+		failures = append(failures, f)
+		i = j - 1
+	}
+	return failures
+}
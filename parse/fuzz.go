@@ -0,0 +1,112 @@
+package parse
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+var fuzzFailingInputRe = regexp.MustCompile(`^\s*Failing input written to (\S+)`)
+
+// FuzzPhase describes how the failing input of a fuzz test was found.
+type FuzzPhase string
+
+const (
+	// FuzzPhaseSeed indicates the input is part of the seed corpus, added with f.Add or stored in
+	// testdata/fuzz, and failed while the fuzz test ran as a regular test.
+	FuzzPhaseSeed FuzzPhase = "seed"
+	// FuzzPhaseFuzzing indicates the input was generated by the fuzzing engine (go test -fuzz) and
+	// written to the corpus.
+	FuzzPhaseFuzzing FuzzPhase = "fuzzing"
+)
+
+// FuzzFailure describes the input that caused a fuzz test to fail.
+type FuzzFailure struct {
+	// Target is the name of the fuzz test, e.g., FuzzFoo.
+	Target  string
+	Package string
+	Phase   FuzzPhase
+	// Entry is the name of the corpus entry, e.g., the hash of a generated input or "seed#0" for
+	// inputs added with f.Add.
+	Entry string
+	// CorpusPath is the path of the failing input relative to the package directory, e.g.,
+	// testdata/fuzz/FuzzFoo/<hash>. Empty for inputs added with f.Add, which have no file.
+	CorpusPath string
+}
+
+// RunCommand returns the go test command that re-runs the fuzz test with only the failing input.
+func (f *FuzzFailure) RunCommand() string {
+	cmd := "go test -run=" + f.Target + "/" + f.Entry
+	if f.Package != "" {
+		cmd += " " + f.Package
+	}
+	return cmd
+}
+
+// isFuzz reports whether the test name belongs to a fuzz test.
+func isFuzz(name string) bool {
+	return strings.HasPrefix(name, "Fuzz")
+}
+
+// IsFuzzProgress reports whether the output is a line the fuzzing engine prints about its
+// progress, e.g., "fuzz: elapsed: 3s, execs: 1000 (333/sec), new interesting: 0 (total: 1)" or
+// "fuzz: minimizing 32-byte failing input file".
+func (e *Event) IsFuzzProgress() bool {
+	return strings.HasPrefix(e.Output, "fuzz: ")
+}
+
+// addFuzzEvent records the failing input of a fuzz test. There are 2 cases:
+//
+//  1. While fuzzing (go test -fuzz), go test writes the failing input to the corpus and reports
+//     "Failing input written to testdata/fuzz/FuzzFoo/<hash>" as output of the fuzz test.
+//  2. When run as a regular test, each corpus entry runs as a subtest of the fuzz test, e.g.,
+//     FuzzFoo/<hash> or FuzzFoo/seed#0, and a failing entry fails its subtest.
+func addFuzzEvent(t *Test, e *Event) {
+	switch {
+	case e.Action == ActionOutput && t.Parent == nil && isFuzz(t.Name):
+		m := fuzzFailingInputRe.FindStringSubmatch(e.Output)
+		if m == nil {
+			return
+		}
+		t.Fuzz = &FuzzFailure{
+			Target:     t.Name,
+			Package:    t.Package,
+			Phase:      FuzzPhaseFuzzing,
+			Entry:      path.Base(m[1]),
+			CorpusPath: m[1],
+		}
+	case e.Action == ActionFail && t.Parent != nil && t.Parent.Parent == nil && isFuzz(t.Parent.Name):
+		// Keep the first failing entry, it's enough to reproduce the failure.
+		if t.Parent.Fuzz != nil {
+			return
+		}
+		f := &FuzzFailure{
+			Target:  t.Parent.Name,
+			Package: t.Package,
+			Phase:   FuzzPhaseSeed,
+			Entry:   t.ShortName(),
+		}
+		if !strings.HasPrefix(f.Entry, "seed#") {
+			f.CorpusPath = path.Join("testdata", "fuzz", f.Target, f.Entry)
+		}
+		t.Parent.Fuzz = f
+	}
+}
+
+// IsReproOutput reports whether the output is one of the lines go test prints to describe the
+// failing input, which are superseded by the FuzzFailure fields. The lines are preceded by an
+// empty line:
+//
+//	Failing input written to testdata/fuzz/FuzzFoo/<hash>
+//	To re-run:
+//	go test -run=FuzzFoo/<hash>
+func (f *FuzzFailure) IsReproOutput(output string) bool {
+	switch strings.TrimSpace(output) {
+	case "",
+		"Failing input written to " + f.CorpusPath,
+		"To re-run:",
+		"go test -run=" + f.Target + "/" + f.Entry:
+		return true
+	}
+	return false
+}
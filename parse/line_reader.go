@@ -0,0 +1,110 @@
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxLineSize is the default maximum size of a single line of go test output. See
+// WithMaxLineSize.
+const DefaultMaxLineSize = 1024 * 1024
+
+// lineReader reads newline-delimited lines. Unlike bufio.Scanner it does not fail on lines longer
+// than its buffer: lines longer than max bytes are cut short and the remainder is discarded.
+type lineReader struct {
+	r   *bufio.Reader
+	max int
+	buf []byte
+}
+
+func newLineReader(r io.Reader, max int) *lineReader {
+	if max <= 0 {
+		max = DefaultMaxLineSize
+	}
+	return &lineReader{
+		r:   bufio.NewReader(r),
+		max: max,
+	}
+}
+
+// next returns the next line, without the trailing newline, and the number of bytes that were
+// discarded because the line exceeded the maximum size. The line is only valid until the next
+// call. At the end of the input, next returns io.EOF.
+func (lr *lineReader) next() (line []byte, dropped int, err error) {
+	lr.buf = lr.buf[:0]
+	for {
+		chunk, err := lr.r.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			dropped += lr.keep(chunk)
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, 0, err
+		}
+		chunk = bytes.TrimSuffix(chunk, []byte("\n"))
+		dropped += lr.keep(chunk)
+		if errors.Is(err, io.EOF) && len(lr.buf) == 0 && dropped == 0 {
+			return nil, 0, io.EOF
+		}
+		break
+	}
+	if dropped == 0 {
+		// Same as bufio.ScanLines, drop a trailing carriage return.
+		lr.buf = bytes.TrimSuffix(lr.buf, []byte("\r"))
+	}
+	return lr.buf, dropped, nil
+}
+
+// keep appends as much of the chunk as fits within the maximum line size, and returns the number
+// of bytes that did not fit.
+func (lr *lineReader) keep(chunk []byte) int {
+	room := max(lr.max-len(lr.buf), 0)
+	n := min(room, len(chunk))
+	lr.buf = append(lr.buf, chunk[:n]...)
+	return len(chunk) - n
+}
+
+var outputKey = []byte(`"Output":"`)
+
+// newTruncatedEvent decodes an event from a line that was cut short because it exceeded the
+// maximum line size. This works because test2json writes the Output field last, or followed only
+// by small fields, so the cut almost always falls within the output:
+//
+//	{"Time":"...","Action":"output","Package":"...","Test":"...","Output":"a very long li
+//
+// The output string is closed, along with a marker reporting how many bytes were discarded, see
+// truncatedMarker.
+func newTruncatedEvent(head []byte, dropped int) (*Event, error) {
+	i := bytes.Index(head, outputKey)
+	if i < 0 {
+		return nil, errors.New("truncated line has no output field")
+	}
+	start := i + len(outputKey)
+	output := head[start:]
+	// Drop an incomplete escape sequence at the end of the cut, such as `\` or `\u00`.
+	if j := bytes.LastIndexByte(output, '\\'); j >= 0 && len(output)-j < 6 {
+		var n int
+		for k := j; k >= 0 && output[k] == '\\'; k-- {
+			n++
+		}
+		// An odd number of backslashes means output[j] starts an escape sequence.
+		if n%2 == 1 && (len(output)-j < 2 || output[j+1] == 'u') {
+			output = output[:j]
+		}
+	}
+	line := make([]byte, 0, start+len(output)+64)
+	line = append(line, head[:start]...)
+	line = append(line, output...)
+	line = append(line, truncatedMarker(dropped)...)
+	line = append(line, `\n"}`...)
+	return NewEvent(line)
+}
+
+// truncatedMarker returns the marker appended to a line that was cut short, reporting how many
+// bytes were discarded.
+func truncatedMarker(dropped int) string {
+	return fmt.Sprintf("... [truncated %d bytes]", dropped)
+}
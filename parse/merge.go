@@ -0,0 +1,196 @@
+package parse
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Merge combines the summaries of separate go test runs, such as CI shards that each write their
+// own JSON output, into a single summary. The inputs are not modified, and the merged summary only
+// shares events, and the values parsed from them, with them.
+//
+// Packages that appear in more than one summary are reconciled as follows:
+//
+//   - Tests are combined by name, with the attempts of all runs, see Test.Attempts. If the same
+//     test ran more than once, a failed run takes precedence over a run that never finished, which
+//     takes precedence over a passed run, which takes precedence over a skipped run. The attempts
+//     are ordered accordingly, the run that takes precedence last.
+//   - The package fails if it failed in any summary, panicked, timed out, had a data race or
+//     failed to build.
+//   - Elapsed is the longest of all runs, i.e., the wall time of shards that run in parallel.
+//     Cached runs report no elapsed time.
+//   - The package is only marked as cached, [no test files] or [no tests to run] if that is the
+//     case in every summary, e.g., a shard that runs no tests of a package does not hide the tests
+//     run by another shard.
+//   - Coverage is the highest reported coverage, since per-statement coverage is not available.
+func Merge(summaries ...*GoTestSummary) *GoTestSummary {
+	merged := &GoTestSummary{
+		Packages: make(map[string]*Package),
+	}
+	for _, s := range summaries {
+		for _, b := range sortedBuilds(s.Builds) {
+			mb := merged.getBuild(b.ImportPath)
+			if len(mb.Events) == 0 || (b.Failed && !mb.Failed) {
+				mb.Events = slices.Clone(b.Events)
+			}
+			mb.Failed = mb.Failed || b.Failed
+		}
+	}
+	runs := make(map[string][]*Package)
+	for _, s := range summaries {
+		for name, pkg := range s.Packages {
+			runs[name] = append(runs[name], pkg)
+		}
+	}
+	for name, pkgs := range runs {
+		pkg := mergePackages(pkgs)
+		if pkg.FailedBuild != nil {
+			pkg.FailedBuild = merged.Builds[pkg.FailedBuild.ImportPath]
+		}
+		merged.Packages[name] = pkg
+	}
+	return merged
+}
+
+// mergePackages merges the runs of the same package into a new package, a single run included.
+func mergePackages(runs []*Package) *Package {
+	pkg := newPackage()
+	summary := *runs[0].Summary
+	summary.Elapsed = 0
+	pkg.Summary = &summary
+	pkg.Cached, pkg.NoTestFiles, pkg.NoTests = true, true, true
+	for _, run := range runs {
+		// Each run is shuffled with its own seed, keep the seed of the first failed run, if any.
+		// Note, summary.Action is updated below, so it is the outcome of the runs so far.
+		if run.Shuffled && (!pkg.Shuffled || run.Summary.Action == ActionFail && summary.Action != ActionFail) {
+			pkg.Shuffled = true
+			pkg.ShuffleSeed = run.ShuffleSeed
+		}
+		summary.Elapsed = max(summary.Elapsed, run.Summary.Elapsed)
+		if summary.Package == "" {
+			summary.Package = run.Summary.Package
+		}
+		if actionRank(run.Summary.Action) > actionRank(summary.Action) {
+			summary.Action = run.Summary.Action
+			summary.Output = run.Summary.Output
+		}
+		if !run.StartTime.IsZero() && (pkg.StartTime.IsZero() || run.StartTime.Before(pkg.StartTime)) {
+			pkg.StartTime = run.StartTime
+		}
+		pkg.Cached = pkg.Cached && run.Cached
+		pkg.NoTestFiles = pkg.NoTestFiles && run.NoTestFiles
+		pkg.NoTests = pkg.NoTests && run.NoTests
+		pkg.NoTestSlice = append(pkg.NoTestSlice, run.NoTestSlice...)
+		pkg.Output = append(pkg.Output, run.Output...)
+		if run.Cover {
+			pkg.Cover = true
+			pkg.Coverage = max(pkg.Coverage, run.Coverage)
+		}
+		if run.Timeout != nil && pkg.Timeout == nil {
+			pkg.Timeout = run.Timeout
+		}
+		if run.HasPanic {
+			pkg.HasPanic = true
+			pkg.PanicEvents = append(pkg.PanicEvents, run.PanicEvents...)
+		}
+		pkg.HasDataRace = pkg.HasDataRace || run.HasDataRace
+		for _, name := range run.DataRaceTests {
+			if !slices.Contains(pkg.DataRaceTests, name) {
+				pkg.DataRaceTests = append(pkg.DataRaceTests, name)
+			}
+		}
+		for _, r := range run.DataRaces {
+			race := *r
+			race.Tests = slices.Clone(r.Tests)
+			race.Events = slices.Clone(r.Events)
+			pkg.addDataRace(&race)
+		}
+		if run.HasFailedBuildOrSetup {
+			pkg.HasFailedBuildOrSetup = true
+			if pkg.FailedBuild == nil {
+				pkg.FailedBuild = run.FailedBuild
+			}
+		}
+		pkg.Benchmarks = append(pkg.Benchmarks, run.Benchmarks...)
+	}
+	if pkg.HasPanic || pkg.Timeout != nil || pkg.HasDataRace || pkg.HasFailedBuildOrSetup {
+		summary.Action = ActionFail
+	}
+	for _, t := range mergeTests(runs) {
+		pkg.addTest(t)
+	}
+	for _, r := range pkg.DataRaces {
+		pkg.linkDataRace(r)
+	}
+	return pkg
+}
+
+// mergeTests returns new tests that combine the events of all runs, in the order the tests were
+// first seen. The events of a test that ran more than once are ordered by the status of the run,
+// see Merge, so the status of the merged test is that of the run that takes precedence.
+func mergeTests(runs []*Package) []*Test {
+	var order []string
+	byName := make(map[string][]*Test)
+	for _, run := range runs {
+		for _, t := range run.Tests {
+			if _, ok := byName[t.Name]; !ok {
+				order = append(order, t.Name)
+			}
+			byName[t.Name] = append(byName[t.Name], t)
+		}
+	}
+	tests := make([]*Test, 0, len(order))
+	for _, name := range order {
+		ts := byName[name]
+		// Runs with the same status keep the order of the summaries.
+		slices.SortStableFunc(ts, func(a, b *Test) int {
+			return actionRank(a.Status()) - actionRank(b.Status())
+		})
+		// Like a test that ran more than once in the same run, the values parsed from the output
+		// are those of the last run.
+		last := ts[len(ts)-1]
+		t := &Test{
+			Name:       last.Name,
+			Package:    last.Package,
+			Fuzz:       last.Fuzz,
+			Example:    last.Example,
+			Failures:   slices.Clone(last.Failures),
+			Assertions: slices.Clone(last.Assertions),
+			Attrs:      maps.Clone(last.Attrs),
+			Artifacts:  slices.Clone(last.Artifacts),
+		}
+		for _, run := range ts {
+			t.Events = append(t.Events, run.Events...)
+		}
+		tests = append(tests, t)
+	}
+	return tests
+}
+
+// actionRank orders the final actions of tests and packages, the higher the rank the more
+// important the outcome.
+func actionRank(action Action) int {
+	switch action {
+	case ActionFail:
+		return 4
+	case ActionIncomplete:
+		return 3
+	case ActionPass:
+		return 2
+	case ActionSkip:
+		return 1
+	}
+	return 0
+}
+
+func sortedBuilds(builds map[string]*Build) []*Build {
+	s := make([]*Build, 0, len(builds))
+	for _, b := range builds {
+		s = append(s, b)
+	}
+	slices.SortFunc(s, func(a, b *Build) int {
+		return strings.Compare(a.ImportPath, b.ImportPath)
+	})
+	return s
+}
@@ -1,6 +1,9 @@
 package parse
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Package is the representation of a single package being tested. The
 // summary field is an event that contains all relevant information about the
@@ -25,9 +28,19 @@ type Package struct {
 	// a non-empty test name.
 	NoTestSlice []*Event
 
+	// Output holds the output of the package that is not attributed to a test, e.g., logged by
+	// TestMain or an init function, in the order it was received. The lines go test prints to
+	// report the result of the package, such as PASS or ok, are left out.
+	Output []*Event
+
 	// Cached indicates whether the test result was obtained from the cache.
 	Cached bool
 
+	// Shuffled reports whether the tests ran in random order, with go test -shuffle, and
+	// ShuffleSeed is the seed that orders them the same way again, see ShuffleCommand.
+	Shuffled    bool
+	ShuffleSeed int64
+
 	// Cover reports whether the package contains coverage (go test run with -cover)
 	Cover    bool
 	Coverage float64
@@ -37,14 +50,39 @@ type Package struct {
 	// Once a package has been marked HasPanic all subsequent events are added to PanicEvents.
 	PanicEvents []*Event
 
+	// Timeout is set if the test binary ran longer than the go test -timeout flag allows, and nil
+	// otherwise. Once set, all subsequent events are added to Timeout.Events.
+	Timeout *Timeout
+
 	// HasDataRace marks the entire package as having a data race.
 	HasDataRace bool
 	// DataRaceTests captures an individual test names as having a data race.
 	DataRaceTests []string
+	// DataRaces holds the data races reported by the race detector, in the order they were first
+	// reported. Each race is reported once, along with the tests that triggered it.
+	DataRaces []*DataRace
+	// race holds the events of a data race report until the report is complete, and raceTest the
+	// test that triggered it. raceSeparator is the last output event if it may start a report.
+	race          []*Event
+	raceTest      string
+	raceSeparator *Event
 
 	// HasFailedBuildOrSetup marks the package as having a failed build or setup.
 	// Example: [build failed] or [setup failed]
 	HasFailedBuildOrSetup bool
+	// FailedBuild is the build that caused the package to fail, which may be the build of another
+	// package, such as a dependency. Nil if the package did not fail to build, or the build output
+	// was not captured.
+	FailedBuild *Build
+
+	// Benchmarks holds benchmark results in the order they were reported. Benchmarks run with
+	// -count=N or -cpu=a,b have one result per run.
+	Benchmarks []*Benchmark
+	// tests indexes Tests by name.
+	tests map[string]*Test
+	// partialBenchmarkLines holds benchmark result lines, keyed by test name, that go test split
+	// across multiple output events and have not been completed yet.
+	partialBenchmarkLines map[string]string
 }
 
 // newPackage initializes and returns a Package.
@@ -64,15 +102,104 @@ func (p *Package) AddEvent(event *Event) {
 			Name:    event.Test,
 			Package: event.Package,
 		}
-		p.Tests = append(p.Tests, t)
+		p.addTest(t)
 	}
 
 	t.Events = append(t.Events, event)
+	addFuzzEvent(t, event)
+	addAttrEvent(t, event)
+	if event.Action == ActionFail {
+		if isExample(t.Name) {
+			t.Example = parseExampleFailure(t.Events)
+		}
+		attempts := t.Attempts()
+		t.Failures = parseFailures(attempts[len(attempts)-1].Events)
+		t.Assertions = parseAssertions(t.Failures)
+	}
+}
+
+// addTest adds a new test to the package and links it to its parent, if any.
+func (p *Package) addTest(t *Test) {
+	p.Tests = append(p.Tests, t)
+	if p.tests == nil {
+		p.tests = make(map[string]*Test)
+	}
+	p.tests[t.Name] = t
+	p.linkParent(t)
+}
+
+// addBenchmarkOutput collects benchmark results from output events. go test may split a single
+// result line across events, e.g., "BenchmarkFoo-8 \t" followed by "1000\t1234 ns/op\n", so
+// partial lines are buffered until the line is complete.
+//
+// Note, results of benchmarks run with -cpu are sometimes reported without a test name, so this
+// must be called with package-level output too.
+func (p *Package) addBenchmarkOutput(event *Event) {
+	partial, ok := p.partialBenchmarkLines[event.Test]
+	if !ok && !strings.HasPrefix(event.Output, "Benchmark") {
+		return
+	}
+	line := partial + event.Output
+	if !strings.HasSuffix(line, "\n") {
+		if p.partialBenchmarkLines == nil {
+			p.partialBenchmarkLines = make(map[string]string)
+		}
+		p.partialBenchmarkLines[event.Test] = line
+		return
+	}
+	delete(p.partialBenchmarkLines, event.Test)
+	if b, ok := parseBenchmarkLine(line); ok {
+		b.Package = event.Package
+		p.Benchmarks = append(p.Benchmarks, b)
+	}
+}
+
+// linkParent attaches a newly added test to its closest ancestor, if any. Subtest names are
+// separated by "/", but a subtest name passed to t.Run may itself contain a "/", so walk up until
+// a known test is found.
+func (p *Package) linkParent(t *Test) {
+	name := t.Name
+	for {
+		i := strings.LastIndex(name, "/")
+		if i < 0 {
+			return
+		}
+		name = name[:i]
+		if parent := p.GetTest(name); parent != nil {
+			t.Parent = parent
+			parent.Children = append(parent.Children, t)
+			return
+		}
+	}
+}
+
+// Root returns the top-level tests of the package, i.e., tests that are not subtests, in the order
+// they were first seen.
+func (p *Package) Root() []*Test {
+	var tests []*Test
+	for _, t := range p.Tests {
+		if t.Parent == nil {
+			tests = append(tests, t)
+		}
+	}
+	return tests
+}
+
+// Walk calls fn for every test in the package, visiting each top-level test followed by its
+// subtests depth-first. If fn returns false, the subtests of that test are not visited.
+func (p *Package) Walk(fn func(*Test) bool) {
+	for _, t := range p.Root() {
+		t.Walk(fn)
+	}
 }
 
 // GetTest returns a test based on given name, if no test is found
 // return nil
 func (p *Package) GetTest(name string) *Test {
+	if p.tests != nil {
+		return p.tests[name]
+	}
+	// The package was not built with AddEvent, fall back to a linear scan.
 	for _, t := range p.Tests {
 		if t.Name == name {
 			return t
@@ -82,7 +209,7 @@ func (p *Package) GetTest(name string) *Test {
 }
 
 // TestsByAction returns all tests that identify as one of the following
-// actions: pass, skip or fail.
+// actions: pass, skip, fail or incomplete.
 //
 // An empty slice if returned if there are no tests.
 func (p *Package) TestsByAction(action Action) []*Test {
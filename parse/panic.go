@@ -0,0 +1,212 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Panic describes a panic reported by a test binary, parsed from the panic message and the
+// goroutine dump that follows it:
+//
+//	panic: runtime error: invalid memory address or nil pointer dereference [recovered]
+//		panic: runtime error: invalid memory address or nil pointer dereference
+//	[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x1112389]
+//
+//	goroutine 18 [running]:
+//	testing.tRunner.func1(0xc0000b6300)
+//		/usr/local/go/src/testing/testing.go:792 +0x387
+//	...
+//	created by testing.(*T).Run in goroutine 1
+//		/usr/local/go/src/testing/testing.go:878 +0x353
+type Panic struct {
+	// Test is the name of the test that panicked, if known.
+	Test string
+	// Message is the value passed to panic, without the "panic: " prefix. A recovered and
+	// re-panicked value is reported once.
+	Message string
+	// Signal is the signal that caused the panic, if any, e.g., "[signal SIGSEGV: segmentation
+	// violation code=0x1 addr=0x0 pc=0x1112389]".
+	Signal string
+	// Goroutine is the goroutine that panicked, and nil if the output has no goroutine dump.
+	Goroutine *Goroutine
+	// Others holds the other goroutines in the dump, if any, e.g., with GOTRACEBACK=all or after a
+	// test timed out. Goroutines with identical stacks are reported once, see Goroutine.IDs.
+	Others []*Goroutine
+}
+
+// Goroutine is a goroutine in a goroutine dump.
+type Goroutine struct {
+	// IDs holds the IDs of the goroutines with this stack, in the order they were reported. There
+	// is more than one if identical goroutines were deduplicated.
+	IDs []int
+	// State is the state of the goroutine, e.g., "running" or "chan receive, 2 minutes".
+	State string
+	// Frames holds the stack of the goroutine, the most recent call first.
+	Frames []*Frame
+	// CreatedBy is the go statement that started the goroutine, and nil for the main goroutine.
+	CreatedBy *Frame
+}
+
+// Frame is a single call in a goroutine stack.
+type Frame struct {
+	// Func is the fully qualified function name, e.g., "example.com/foo.TestFoo.func1", without
+	// arguments.
+	Func string
+	// File is the absolute path of the source file, and Line the line number in that file.
+	File string
+	Line int
+}
+
+// IsTest reports whether the frame is in a test file, i.e., a file ending in _test.go.
+func (f *Frame) IsTest() bool {
+	return strings.HasSuffix(f.File, "_test.go")
+}
+
+var (
+	goroutineHeaderRe = regexp.MustCompile(`^goroutine (\d+)(?: [^\[]*)? \[([^\]]*)\]:$`)
+	frameLocationRe   = regexp.MustCompile(`^\t(.+):(\d+)(?: \+0x[0-9a-f]+)?$`)
+	recoveredRe       = regexp.MustCompile(` \[recovered(?:, repanicked)?\]$`)
+)
+
+// Panic returns the panic of the package, parsed from PanicEvents, or nil if the package did not
+// panic.
+func (p *Package) Panic() *Panic {
+	if !p.HasPanic {
+		return nil
+	}
+	var lines []string
+	for _, e := range p.PanicEvents {
+		if e.Action != ActionOutput {
+			continue
+		}
+		lines = append(lines, strings.TrimSuffix(e.Output, "\n"))
+	}
+	return parsePanic(p.Summary.Test, lines)
+}
+
+// parsePanic parses the panic message and goroutine dump from output lines, starting at the
+// "panic: " line.
+func parsePanic(test string, lines []string) *Panic {
+	p := &Panic{Test: test}
+	var i int
+	for ; i < len(lines); i++ {
+		if message, ok := strings.CutPrefix(lines[i], "panic: "); ok {
+			p.Message = recoveredRe.ReplaceAllString(message, "")
+			i++
+			break
+		}
+	}
+	// Lines up to the goroutine dump are either the re-panicked value, the signal or more lines of
+	// a multi-line panic message.
+	for ; i < len(lines) && !goroutineHeaderRe.MatchString(lines[i]); i++ {
+		switch line := lines[i]; {
+		case strings.HasPrefix(line, "\tpanic: "), line == "":
+		case strings.HasPrefix(line, "[signal "):
+			p.Signal = line
+		case p.Signal == "":
+			p.Message += "\n" + line
+		}
+	}
+	var goroutines []*Goroutine
+	for i < len(lines) {
+		var g *Goroutine
+		g, i = parseGoroutine(lines, i)
+		if g == nil {
+			break
+		}
+		goroutines = append(goroutines, g)
+	}
+	if len(goroutines) == 0 {
+		return p
+	}
+	p.Goroutine = goroutines[0]
+	seen := make(map[string]*Goroutine)
+	for _, g := range goroutines[1:] {
+		key := g.key()
+		if same, ok := seen[key]; ok {
+			same.IDs = append(same.IDs, g.IDs...)
+			continue
+		}
+		seen[key] = g
+		p.Others = append(p.Others, g)
+	}
+	return p
+}
+
+// parseGoroutine parses the goroutine that starts at lines[i], and returns it along with the index
+// of the line after it. It returns nil if lines[i] is not a goroutine header.
+func parseGoroutine(lines []string, i int) (*Goroutine, int) {
+	ss := goroutineHeaderRe.FindStringSubmatch(lines[i])
+	if ss == nil {
+		return nil, i
+	}
+	id, _ := strconv.Atoi(ss[1])
+	g := &Goroutine{IDs: []int{id}, State: ss[2]}
+	for i++; i < len(lines) && lines[i] != ""; i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "...") {
+			// A location without a function, or a note such as "...additional frames elided...".
+			continue
+		}
+		if i+1 >= len(lines) {
+			break
+		}
+		loc := frameLocationRe.FindStringSubmatch(lines[i+1])
+		if loc == nil {
+			// Not part of the dump, e.g., the "FAIL" line that follows it.
+			break
+		}
+		f := &Frame{File: loc[1]}
+		f.Line, _ = strconv.Atoi(loc[2])
+		i++
+		if createdBy, ok := strings.CutPrefix(line, "created by "); ok {
+			createdBy, _, _ = strings.Cut(createdBy, " in goroutine ")
+			f.Func = createdBy
+			g.CreatedBy = f
+			continue
+		}
+		f.Func = trimArgs(line)
+		g.Frames = append(g.Frames, f)
+	}
+	// Skip the blank line between goroutines.
+	for i < len(lines) && lines[i] == "" {
+		i++
+	}
+	return g, i
+}
+
+// key identifies goroutines with the same state and stack.
+func (g *Goroutine) key() string {
+	var b strings.Builder
+	b.WriteString(g.State)
+	frames := g.Frames
+	if g.CreatedBy != nil {
+		frames = append(frames[:len(frames):len(frames)], g.CreatedBy)
+	}
+	for _, f := range frames {
+		b.WriteString("\n" + f.Func + " " + f.File + ":" + strconv.Itoa(f.Line))
+	}
+	return b.String()
+}
+
+// trimArgs removes the arguments from a function call in a stack trace, e.g.,
+// "testing.(*T).Run(0xc0000b6300, {0x116177e, 0xe})" becomes "testing.(*T).Run".
+func trimArgs(call string) string {
+	if !strings.HasSuffix(call, ")") {
+		return call
+	}
+	var depth int
+	for i := len(call) - 1; i >= 0; i-- {
+		switch call[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				return call[:i]
+			}
+		}
+	}
+	return call
+}
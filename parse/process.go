@@ -1,16 +1,12 @@
 package parse
 
 import (
-	"bufio"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
-	"time"
 )
 
 // ErrNotParsable indicates the event line was not parsable.
@@ -20,130 +16,16 @@ var ErrNotParsable = errors.New("failed to parse")
 // and parses go test output in JSON format until EOF.
 //
 // Note, Process will attempt to parse up to 50 lines before returning an error.
+//
+// Use NewStream to consume events as they are parsed, instead of waiting for EOF.
 func Process(r io.Reader, optionsFunc ...OptionsFunc) (*GoTestSummary, error) {
-	option := &options{}
-	for _, f := range optionsFunc {
-		f(option)
-	}
-	summary := &GoTestSummary{
-		Packages: make(map[string]*Package),
-	}
-
-	noisy := []string{
-		// 1. Filter out noisy output, such as === RUN, === PAUSE, etc.
-		updatePrefixRun,
-		updatePrefixPause,
-		updatePrefixCont,
-		updatePrefixPass,
-		updatePrefixSkip,
-		// 2. Filter out report output, such as --- PASS: and --- SKIP:
-		resultPrefixPass,
-		resultPrefixSkip,
-	}
-	isNoisy := func(e *Event) bool {
-		output := strings.TrimSpace(e.Output)
-		// If the event is a big pass or fail, we can safely discard it. These are typically the
-		// lines preceding the package summary line. For example:
-		//
-		//  PASS
-		//  ok      fmt 0.144s
-		if e.Test == "" && (output == bigPass || output == bigFail) {
-			return true
-		}
-		for _, prefix := range noisy {
-			if strings.HasPrefix(output, prefix) {
-				return true
-			}
-		}
-		return false
-
-	}
-
-	sc := bufio.NewScanner(r)
-	var started bool
-	var badLines int
-	for sc.Scan() {
-		// Scan up-to 50 lines for a parsable event, if we get one, expect
-		// no errors to follow until EOF.
-		e, err := NewEvent(sc.Bytes())
+	stream := NewStream(r, optionsFunc...)
+	for _, err := range stream.Events() {
 		if err != nil {
-			// We failed to parse a go test JSON event, but there are special cases for failed
-			// builds, setup, etc. Let special case these and bubble them up in the summary
-			// if the output belongs to a package.
-			summary.AddRawEvent(sc.Text())
-
-			badLines++
-			if started || badLines > 50 {
-				var syntaxError *json.SyntaxError
-				if errors.As(err, &syntaxError) {
-					err = fmt.Errorf("line %d JSON error: %s: %w", badLines, syntaxError.Error(), ErrNotParsable)
-					if option.debug {
-						// In debug mode we can surface a more verbose error message which
-						// contains the current line number and exact JSON parsing error.
-						fmt.Fprintf(os.Stderr, "debug: %s", err.Error())
-					}
-				}
-				return nil, err
-			}
-			if option.follow && option.w != nil {
-				fmt.Fprintf(option.w, "%s\n", sc.Bytes())
-			}
-			continue
-		}
-		started = true
-
-		// TODO(mf): when running tparse locally it's very useful to see progress for long-running
-		// test suites. Since we have access to the event we can send it on a chan
-		// or just directly update a spinner-like component. This cannot be run with the
-		// follow option. Lastly, need to consider what local vs CI behavior would be like.
-		// Depending on how often the frames update, this could cause a lot of noise, so maybe
-		// we need to expose an interval option, so in CI it would update infrequently.
-
-		// Optionally, as test output is piped to us, we write the plain
-		// text Output as if go test was run without the -json flag.
-		if (option.follow || option.followVerbose) && option.w != nil {
-			if !option.followVerbose && isNoisy(e) {
-				continue
-			}
-			if e.Output != "" && option.includeTimestamp {
-				fmt.Fprint(option.w, e.Time.Format(time.RFC3339)+" "+e.Output)
-			} else {
-				fmt.Fprint(option.w, e.Output)
-			}
-
+			return nil, err
 		}
-		// Progress is a special case of follow, where we only print the
-		// progress of the test suite, but not the output.
-		if option.progress && option.w != nil {
-			printProgress(option.progressOutput, e, summary.Packages)
-		}
-
-		// TODO(mf): special case build output for now. Need to understand how to better handle this
-		// But we don't want to swallow important build errors. There is a class of build output
-		// that is bengin like: https://github.com/golang/go/issues/61229
-		//
-		//  Example:
-		//  ld: warning: '.../go.o' has malformed LC_DYSYMTAB, expected 92 undefined symbols to start at index 15983, found 102 undefined symbol
-		//
-		// TL;DR - output ALL build output to stderr and exclude it from being added to test events
-		if e.ImportPath != "" {
-			if e.Output != "" {
-				fmt.Fprint(os.Stderr, e.Output)
-			}
-			continue
-		}
-
-		summary.AddEvent(e)
-	}
-	if err := sc.Err(); err != nil {
-		return nil, fmt.Errorf("received scanning error: %w", err)
 	}
-	// Entire input has been scanned and no go test JSON output was found.
-	if !started {
-		return nil, ErrNotParsable
-	}
-
-	return summary, nil
+	return stream.Summary(), nil
 }
 
 // printProgress prints a single summary line for each PASS or FAIL package.
@@ -189,6 +71,12 @@ func printProgress(w progressWriter, e *Event, summary map[string]*Package) {
 
 type GoTestSummary struct {
 	Packages map[string]*Package
+
+	// Builds holds the build output reported by the go command, keyed by ImportPath. Packages
+	// that failed to build link to the root cause with Package.FailedBuild.
+	Builds map[string]*Build
+	// rawBuild holds plain text build output until the package it belongs to is known.
+	rawBuild *Build
 }
 
 func (s *GoTestSummary) AddRawEvent(str string) {
@@ -205,8 +93,27 @@ func (s *GoTestSummary) AddRawEvent(str string) {
 			pkg.Summary.Action = ActionFail
 			pkg.Summary.Output = failMessage
 			pkg.HasFailedBuildOrSetup = true
+			if failMessage == "build failed" {
+				s.linkRawBuild(pkgName, pkg)
+			}
+			return
 		}
 	}
+	s.addRawBuildOutput(str)
+}
+
+// BuildFailures returns the builds that failed, sorted by ImportPath.
+func (s *GoTestSummary) BuildFailures() []*Build {
+	var builds []*Build
+	for _, b := range s.Builds {
+		if b.Failed {
+			builds = append(builds, b)
+		}
+	}
+	sort.Slice(builds, func(i, j int) bool {
+		return builds[i].ImportPath < builds[j].ImportPath
+	})
+	return builds
 }
 
 func (s *GoTestSummary) AddEvent(e *Event) {
@@ -219,6 +126,9 @@ func (s *GoTestSummary) AddEvent(e *Event) {
 	pkg, ok := s.Packages[e.Package]
 	if !ok {
 		pkg = newPackage()
+		// The summary is replaced by the final event of the package. Name the package in case
+		// there is none, e.g., the output was truncated.
+		pkg.Summary.Package = e.Package
 		s.Packages[e.Package] = pkg
 	}
 	// Capture the start time of the package. This is only available in go1.20 and above.
@@ -226,6 +136,19 @@ func (s *GoTestSummary) AddEvent(e *Event) {
 		pkg.StartTime = e.Time
 		return
 	}
+	// Special case timeouts, the test binary is stopped with a panic but the tests that did not
+	// run into the timeout are unaffected.
+	if e.IsTimeout() {
+		pkg.Summary.Action = ActionFail
+		pkg.Summary.Package = e.Package
+	}
+	if pkg.Timeout != nil || e.IsTimeout() {
+		pkg.addTimeoutEvent(e)
+		if e.LastLine() {
+			pkg.Summary = e
+		}
+		return
+	}
 	// Special case panics.
 	if e.IsPanic() {
 		pkg.HasPanic = true
@@ -239,16 +162,31 @@ func (s *GoTestSummary) AddEvent(e *Event) {
 		return
 	}
 	if e.LastLine() {
+		// Since go1.24 the final event of a package that failed to build links to the root cause.
+		if e.FailedBuild != "" {
+			pkg.HasFailedBuildOrSetup = true
+			pkg.FailedBuild = s.getBuild(e.FailedBuild)
+			pkg.FailedBuild.Failed = true
+		}
+		// Preserve the [build failed] or [setup failed] message, if any.
+		message := pkg.Summary.Output
 		pkg.Summary = e
+		if pkg.HasFailedBuildOrSetup {
+			pkg.Summary.Output = message
+		}
 		return
 	}
+	pkg.addRaceEvent(e)
 	// Parse the raw output to add additional metadata to Package.
 	switch {
-	case e.IsRace():
-		pkg.HasDataRace = true
-		if e.Test != "" {
-			pkg.DataRaceTests = append(pkg.DataRaceTests, e.Test)
-		}
+	case e.Test == "" && failedBuildOrSetupRe.MatchString(e.Output):
+		// Since go1.24 the [build failed] line is part of the JSON output, see AddRawEvent for
+		// older versions.
+		ss := failedBuildOrSetupRe.FindStringSubmatch(e.Output)
+		pkg.HasFailedBuildOrSetup = true
+		pkg.Summary.Package = e.Package
+		pkg.Summary.Action = ActionFail
+		pkg.Summary.Output = strings.TrimSpace(ss[2])
 	case e.IsCached():
 		pkg.Cached = true
 	case e.NoTestFiles():
@@ -272,10 +210,22 @@ func (s *GoTestSummary) AddEvent(e *Event) {
 			pkg.Cover = true
 			pkg.Coverage = cover
 		}
+		if e.Test == "" {
+			if seed, ok := e.Shuffle(); ok {
+				pkg.Shuffled = true
+				pkg.ShuffleSeed = seed
+			}
+		}
+	}
+	if e.Action == ActionOutput {
+		pkg.addBenchmarkOutput(e)
 	}
 	// We captured all the necessary package-level information, if the event
-	// is output and does not have a test name, discard it.
+	// is output and does not have a test name, keep it with the package instead.
 	if e.DiscardEmptyTestOutput() {
+		if !e.isPackageResult() {
+			pkg.Output = append(pkg.Output, e)
+		}
 		return
 	}
 	pkg.AddEvent(e)
@@ -290,18 +240,24 @@ func (s *GoTestSummary) GetSortedPackages(sorter PackageSorter) []*Package {
 	return packages
 }
 
+// ExitCode reports 2 if any package failed to build or set up, 1 if any package failed, panicked,
+// timed out, had a data race or has tests that never finished, and 0 otherwise.
 func (s *GoTestSummary) ExitCode() int {
+	var code int
 	for _, pkg := range s.Packages {
 		switch {
 		case pkg.HasFailedBuildOrSetup:
+			// Takes precedence over everything else, no need to look any further.
 			return 2
-		case pkg.HasPanic, pkg.HasDataRace:
-			return 1
+		case pkg.HasPanic, pkg.HasDataRace, pkg.Timeout != nil:
+			code = 1
 		case len(pkg.DataRaceTests) > 0:
-			return 1
+			code = 1
 		case pkg.Summary.Action == ActionFail:
-			return 1
+			code = 1
+		case len(pkg.TestsByAction(ActionIncomplete)) > 0:
+			code = 1
 		}
 	}
-	return 0
+	return code
 }
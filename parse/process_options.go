@@ -19,6 +19,8 @@ type options struct {
 	progressOutput progressWriter
 
 	includeTimestamp bool
+
+	maxLineSize int
 }
 
 type OptionsFunc func(o *options)
@@ -50,3 +52,10 @@ func WithProgressOutput(w progressWriter) OptionsFunc {
 func WithIncludeTimestamp(b bool) OptionsFunc {
 	return func(o *options) { o.includeTimestamp = b }
 }
+
+// WithMaxLineSize sets the maximum size, in bytes, of a single line of go test output. The output
+// of longer lines is truncated with a marker instead of failing to parse. Defaults to
+// DefaultMaxLineSize.
+func WithMaxLineSize(n int) OptionsFunc {
+	return func(o *options) { o.maxLineSize = n }
+}
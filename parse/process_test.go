@@ -0,0 +1,91 @@
+package parse
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// BenchmarkProcess parses synthetic go test output of increasing size. The ns/event metric should
+// stay roughly flat as the input grows, i.e., parsing is linear in the number of events.
+//
+//	go test ./parse -run=^$ -bench=Process -benchmem
+func BenchmarkProcess(b *testing.B) {
+	for _, tests := range []int{1_000, 10_000, 100_000} {
+		input := syntheticOutput(10, tests/10)
+		events := bytes.Count(input, []byte("\n"))
+		b.Run(fmt.Sprintf("tests=%d", tests), func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			for range b.N {
+				summary, err := Process(bytes.NewReader(input))
+				if err != nil {
+					b.Fatal(err)
+				}
+				// Exercise the same calls the summary table makes for each package.
+				for _, pkg := range summary.Packages {
+					_ = pkg.TestsByAction(ActionPass)
+					_ = pkg.TestsByAction(ActionFail)
+					_ = pkg.TestsByAction(ActionSkip)
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*events), "ns/event")
+		})
+	}
+}
+
+// syntheticOutput returns go test -json output for the given number of packages, each with the
+// given number of tests. Every 10th test has 2 subtests and every 100th test fails.
+func syntheticOutput(packages, tests int) []byte {
+	var buf bytes.Buffer
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	write := func(pkg, test string, action Action, output string, elapsed float64) {
+		now = now.Add(time.Microsecond)
+		e := fmt.Sprintf(`{"Time":%q,"Action":%q,"Package":%q`, now.Format(time.RFC3339Nano), action, pkg)
+		if test != "" {
+			e += fmt.Sprintf(`,"Test":%q`, test)
+		}
+		if output != "" {
+			e += fmt.Sprintf(`,"Output":%q`, output)
+		}
+		if action == ActionPass || action == ActionFail {
+			e += fmt.Sprintf(`,"Elapsed":%g`, elapsed)
+		}
+		buf.WriteString(e + "}\n")
+	}
+	start := func(pkg, name string) {
+		write(pkg, name, ActionRun, "", 0)
+		write(pkg, name, ActionOutput, "=== RUN   "+name+"\n", 0)
+		write(pkg, name, ActionOutput, "    synthetic_test.go:42: some log output\n", 0)
+	}
+	finish := func(pkg, name string, action Action) {
+		result := resultPrefixPass
+		if action == ActionFail {
+			result = resultPrefixFail
+		}
+		write(pkg, name, ActionOutput, result+name+" (0.01s)\n", 0)
+		write(pkg, name, action, "", 0.01)
+	}
+	for p := range packages {
+		pkg := fmt.Sprintf("example.com/synthetic/pkg%d", p)
+		write(pkg, "", ActionStart, "", 0)
+		for t := range tests {
+			name := fmt.Sprintf("TestSynthetic%d", t)
+			action := ActionPass
+			if t%100 == 0 {
+				action = ActionFail
+			}
+			start(pkg, name)
+			if t%10 == 0 {
+				start(pkg, name+"/first")
+				finish(pkg, name+"/first", ActionPass)
+				start(pkg, name+"/second")
+				finish(pkg, name+"/second", action)
+			}
+			finish(pkg, name, action)
+		}
+		write(pkg, "", ActionOutput, "FAIL\n", 0)
+		write(pkg, "", ActionFail, "", 1.5)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,203 @@
+package parse
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DataRace is a data race reported by the race detector:
+//
+//	==================
+//	WARNING: DATA RACE
+//	Read at 0x00c0000a8f68 by goroutine 8:
+//	  example.com/foo.RaceFunc.func1()
+//	      /home/user/foo/foo.go:13 +0x3c
+//
+//	Previous write at 0x00c0000a8f68 by goroutine 7:
+//	  example.com/foo.RaceFunc()
+//	      /home/user/foo/foo.go:11 +0x88
+//	  ...
+//
+//	Goroutine 8 (running) created at:
+//	  example.com/foo.RaceFunc()
+//	      /home/user/foo/foo.go:12 +0x70
+//	  ...
+//	==================
+//
+// The same race may be reported more than once, e.g., by different tests calling the same code.
+// Such reports are deduplicated by the code that made the conflicting accesses.
+type DataRace struct {
+	// Tests holds the names of the tests that triggered the race, in the order they were
+	// reported. Empty if the race did not happen in a test, e.g., in TestMain.
+	Tests []string
+	// Access is the access that raced with the Previous access.
+	Access   *RaceAccess
+	Previous *RaceAccess
+
+	// Events are the output events of the reports of the race.
+	Events []*Event
+}
+
+// RaceAccess is one of the two conflicting memory accesses of a data race.
+type RaceAccess struct {
+	// Op is the kind of access, e.g., "Read", "Write" or "Atomic write".
+	Op string
+	// Address is the memory address that was accessed, e.g., "0x00c0000a8f68".
+	Address string
+	// Goroutine is the ID of the goroutine that made the access, or 0 for the main goroutine.
+	Goroutine int
+	// Frames holds the stack of the access, the most recent call first.
+	Frames []*Frame
+	// CreatedAt holds the stack that started the goroutine, if reported, the most recent call
+	// first.
+	CreatedAt []*Frame
+}
+
+const raceSeparator = "=================="
+
+var (
+	raceAccessRe  = regexp.MustCompile(`^(Previous )?(.+?) at (0x[0-9a-f]+) by (?:goroutine (\d+)|main goroutine):$`)
+	raceCreatedRe = regexp.MustCompile(`^Goroutine (\d+) \(\w+\) created at:$`)
+	raceFrameRe   = regexp.MustCompile(`^\s+(.+):(\d+)(?: \+0x[0-9a-f]+)?$`)
+)
+
+// addRaceEvent collects the output of a data race report, from the "WARNING: DATA RACE" line up
+// to the separator that ends it. The separator that precedes the report is included as well.
+func (p *Package) addRaceEvent(e *Event) {
+	if e.Action != ActionOutput {
+		return
+	}
+	separator := strings.HasPrefix(e.Output, raceSeparator)
+	switch {
+	case e.IsRace():
+		p.HasDataRace = true
+		if e.Test != "" && !slices.Contains(p.DataRaceTests, e.Test) {
+			p.DataRaceTests = append(p.DataRaceTests, e.Test)
+		}
+		p.race = nil
+		if p.raceSeparator != nil {
+			p.race = append(p.race, p.raceSeparator)
+		}
+		p.race = append(p.race, e)
+		p.raceTest = e.Test
+	case p.race == nil:
+	case separator:
+		if r := parseDataRace(p.raceTest, append(p.race, e)); r != nil {
+			p.addDataRace(r)
+		}
+		p.race = nil
+		// The separator ends the report, it can't also precede the next one.
+		separator = false
+	default:
+		p.race = append(p.race, e)
+	}
+	p.raceSeparator = nil
+	if separator {
+		p.raceSeparator = e
+	}
+}
+
+// addDataRace adds the race to the package, or merges it with the same race reported before.
+func (p *Package) addDataRace(r *DataRace) {
+	key := r.key()
+	for _, same := range p.DataRaces {
+		if same.key() != key {
+			continue
+		}
+		for _, name := range r.Tests {
+			if !slices.Contains(same.Tests, name) {
+				same.Tests = append(same.Tests, name)
+			}
+		}
+		same.Events = append(same.Events, r.Events...)
+		p.linkDataRace(same)
+		return
+	}
+	p.DataRaces = append(p.DataRaces, r)
+	p.linkDataRace(r)
+}
+
+// linkDataRace adds the race to the tests that triggered it, if they exist.
+func (p *Package) linkDataRace(r *DataRace) {
+	for _, name := range r.Tests {
+		if t := p.GetTest(name); t != nil && !slices.Contains(t.DataRaces, r) {
+			t.DataRaces = append(t.DataRaces, r)
+		}
+	}
+}
+
+// parseDataRace parses the output of a data race report triggered by the named test, if any. It
+// returns nil if the report has no conflicting accesses.
+func parseDataRace(test string, events []*Event) *DataRace {
+	r := &DataRace{Events: events}
+	if test != "" {
+		r.Tests = []string{test}
+	}
+	// created holds the creation stacks of the goroutines, by ID.
+	created := make(map[int]*[]*Frame)
+	// The stack that frames are added to.
+	var frames *[]*Frame
+	for i := 0; i < len(events); i++ {
+		line := strings.TrimSuffix(events[i].Output, "\n")
+		if ss := raceAccessRe.FindStringSubmatch(line); ss != nil {
+			a := &RaceAccess{Op: ss[2], Address: ss[3]}
+			a.Goroutine, _ = strconv.Atoi(ss[4])
+			if ss[1] != "" {
+				// E.g., "Previous write", reported with the same capitalization as the access.
+				a.Op = strings.ToUpper(a.Op[:1]) + a.Op[1:]
+				r.Previous = a
+			} else {
+				r.Access = a
+			}
+			frames = &a.Frames
+			continue
+		}
+		if ss := raceCreatedRe.FindStringSubmatch(line); ss != nil {
+			id, _ := strconv.Atoi(ss[1])
+			frames = new([]*Frame)
+			created[id] = frames
+			continue
+		}
+		if frames == nil || !strings.HasPrefix(line, "  ") || i+1 >= len(events) {
+			frames = nil
+			continue
+		}
+		loc := raceFrameRe.FindStringSubmatch(strings.TrimSuffix(events[i+1].Output, "\n"))
+		if loc == nil {
+			continue
+		}
+		f := &Frame{Func: trimArgs(strings.TrimSpace(line)), File: loc[1]}
+		f.Line, _ = strconv.Atoi(loc[2])
+		*frames = append(*frames, f)
+		i++
+	}
+	if r.Access == nil || r.Previous == nil {
+		return nil
+	}
+	for _, a := range []*RaceAccess{r.Access, r.Previous} {
+		if stack, ok := created[a.Goroutine]; ok {
+			a.CreatedAt = *stack
+		}
+	}
+	return r
+}
+
+// key identifies races with the same conflicting accesses. An access is identified by the code
+// that made it: the frames up to and including the first frame outside the runtime. The callers
+// of that code, such as the test, may differ.
+func (r *DataRace) key() string {
+	var b strings.Builder
+	for _, a := range []*RaceAccess{r.Access, r.Previous} {
+		b.WriteString(a.Op)
+		for _, f := range a.Frames {
+			b.WriteString("\n" + f.Func + " " + f.File + ":" + strconv.Itoa(f.Line))
+			if !strings.HasPrefix(f.Func, "runtime.") {
+				break
+			}
+		}
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,557 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReportVersion is the version of the Report schema. It is incremented whenever a field is removed
+// or its meaning changes. Fields may be added without changing the version, so readers should
+// ignore fields they do not know.
+//
+// Version 1 holds the package and test results, along with the benchmarks, data races, timeouts
+// and, with -coverprofile, the coverage profile of the run.
+const ReportVersion = 1
+
+// ErrNotReport indicates the input is not a Report, e.g., it is go test output.
+var ErrNotReport = errors.New("not a tparse report")
+
+// Report is a machine-readable summary of a test run, written by tparse -format json:
+//
+//	{
+//	  "version": 1,
+//	  "exit_code": 1,
+//	  "counts": {"pass": 12, "fail": 1, "skip": 2, "incomplete": 0},
+//	  "packages": [
+//	    {
+//	      "package": "example.com/foo",
+//	      "status": "fail",
+//	      "elapsed": 0.42,
+//	      "cover": true,
+//	      "coverage": 81.5,
+//	      "counts": {"pass": 12, "fail": 1, "skip": 2, "incomplete": 0},
+//	      "benchmarks": [
+//	        {"name": "BenchmarkFoo", "procs": 8, "iterations": 1000, "ns_per_op": 1234}
+//	      ],
+//	      "tests": [
+//	        {
+//	          "name": "TestFoo",
+//	          "status": "fail",
+//	          "elapsed": 0.01,
+//	          "attempts": 1,
+//	          "failures": [{"file": "foo_test.go", "line": 12, "message": "got 1, want 2"}],
+//	          "output": "=== RUN   TestFoo\n    foo_test.go:12: got 1, want 2\n--- FAIL: TestFoo (0.01s)\n"
+//	        }
+//	      ]
+//	    }
+//	  ]
+//	}
+//
+// Fields that are false, zero or empty are omitted, except for the version, exit code, counts and
+// the package name, status and elapsed time.
+type Report struct {
+	// Version is the version of the schema, see ReportVersion.
+	Version int `json:"version"`
+	// ExitCode is the exit code of tparse, see GoTestSummary.ExitCode.
+	ExitCode int `json:"exit_code"`
+	// Counts are the number of tests of all packages by status.
+	Counts ReportCounts `json:"counts"`
+	// Packages holds the packages sorted by name.
+	Packages []*ReportPackage `json:"packages"`
+	// CoverageProfile is the coverage of the profile read with -coverprofile, if any, see
+	// NewReportCoverageProfile.
+	CoverageProfile *ReportCoverageProfile `json:"coverage_profile,omitempty"`
+}
+
+// ReportCounts are the number of tests by status. Subtests are counted as tests.
+type ReportCounts struct {
+	Pass       int `json:"pass"`
+	Fail       int `json:"fail"`
+	Skip       int `json:"skip"`
+	Incomplete int `json:"incomplete"`
+}
+
+// ReportPackage is the result of a single package.
+type ReportPackage struct {
+	Package string `json:"package"`
+	// Status is the outcome of the package: pass, fail or skip, e.g., for a package without test
+	// files. Incomplete if go test did not report a result.
+	Status Action `json:"status"`
+	// Elapsed is how long the package ran, in seconds.
+	Elapsed float64 `json:"elapsed"`
+	Cached  bool    `json:"cached,omitempty"`
+	// Shuffled reports whether the tests ran in random order, and ShuffleSeed is the seed to run
+	// them in the same order again, see Package.ShuffleCommand.
+	Shuffled    bool  `json:"shuffled,omitempty"`
+	ShuffleSeed int64 `json:"shuffle_seed,omitempty"`
+	// Cover reports whether the package ran with -cover, and Coverage is the percentage of
+	// statements covered.
+	Cover    bool    `json:"cover,omitempty"`
+	Coverage float64 `json:"coverage,omitempty"`
+
+	NoTestFiles bool `json:"no_test_files,omitempty"`
+	NoTests     bool `json:"no_tests,omitempty"`
+	Panic       bool `json:"panic,omitempty"`
+	DataRace    bool `json:"data_race,omitempty"`
+	BuildFailed bool `json:"build_failed,omitempty"`
+	Timeout     bool `json:"timeout,omitempty"`
+	// TimeoutAfter is the go test -timeout that was exceeded, in seconds, and RunningTests holds
+	// the tests that were still running at the time, see Package.Timeout.
+	TimeoutAfter float64              `json:"timeout_after,omitempty"`
+	RunningTests []*ReportRunningTest `json:"running_tests,omitempty"`
+
+	Counts ReportCounts `json:"counts"`
+	// Tests holds the tests of the package, each followed by its subtests.
+	Tests []*ReportTest `json:"tests,omitempty"`
+	// Benchmarks holds the benchmark results in the order they were reported, see
+	// Package.Benchmarks.
+	Benchmarks []*ReportBenchmark `json:"benchmarks,omitempty"`
+	// DataRaces holds the data races reported by the race detector, each reported once, see
+	// Package.DataRaces.
+	DataRaces []*ReportDataRace `json:"data_races,omitempty"`
+	// Output is the output of the package that is not attributed to a test, see Package.Output,
+	// followed by the output of a panic or timeout, if any.
+	Output string `json:"output,omitempty"`
+	// BuildOutput is the output of the build that failed, see Package.FailedBuild.
+	BuildOutput string `json:"build_output,omitempty"`
+}
+
+// ReportTest is the result of a single test.
+type ReportTest struct {
+	Name string `json:"name"`
+	// Status is the outcome of the test: pass, fail, skip or incomplete, see Test.Status.
+	Status Action `json:"status"`
+	// Elapsed is how long the test ran, in seconds.
+	Elapsed float64 `json:"elapsed"`
+	// Attempts is the number of times the test ran, e.g., with go test -count=N.
+	Attempts int  `json:"attempts,omitempty"`
+	Flaky    bool `json:"flaky,omitempty"`
+	// SkipReason is the message the test was skipped with, see Test.SkipReason.
+	SkipReason string `json:"skip_reason,omitempty"`
+	// Attrs holds the attributes the test set with t.Attr, see Test.Attrs.
+	Attrs map[string]string `json:"attrs,omitempty"`
+	// Artifacts holds the directories the test stored output files in, see Test.Artifacts.
+	Artifacts []string `json:"artifacts,omitempty"`
+	// Failures holds the failures reported by a failed test, see Test.Failures.
+	Failures []*ReportFailure `json:"failures,omitempty"`
+	// Output is the output of the last attempt of a failed or incomplete test.
+	Output string `json:"output,omitempty"`
+}
+
+// ReportFailure is a failure reported by a test, see Failure.
+type ReportFailure struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// ReportRunningTest is a test that was still running when the test binary timed out, see
+// RunningTest.
+type ReportRunningTest struct {
+	Name string `json:"name"`
+	// Elapsed is how long the test had been running, in seconds.
+	Elapsed float64 `json:"elapsed,omitempty"`
+}
+
+// ReportBenchmark is a single benchmark result, see Benchmark.
+type ReportBenchmark struct {
+	// Name is the benchmark name without the GOMAXPROCS suffix, which is reported as Procs.
+	Name       string  `json:"name"`
+	Procs      int     `json:"procs,omitempty"`
+	Iterations int64   `json:"iterations"`
+	NsPerOp    float64 `json:"ns_per_op"`
+	MBPerSec   float64 `json:"mb_per_sec,omitempty"`
+	// BytesPerOp and AllocsPerOp are only set when reported, e.g., with -benchmem.
+	BytesPerOp  *float64 `json:"bytes_per_op,omitempty"`
+	AllocsPerOp *float64 `json:"allocs_per_op,omitempty"`
+	// Metrics holds custom metrics reported with b.ReportMetric, keyed by unit.
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+}
+
+// ReportDataRace is a data race reported by the race detector, see DataRace.
+type ReportDataRace struct {
+	// Tests holds the names of the tests that triggered the race.
+	Tests    []string          `json:"tests,omitempty"`
+	Access   *ReportRaceAccess `json:"access"`
+	Previous *ReportRaceAccess `json:"previous"`
+}
+
+// ReportRaceAccess is one of the two conflicting memory accesses of a data race, see RaceAccess.
+type ReportRaceAccess struct {
+	Op        string         `json:"op"`
+	Address   string         `json:"address"`
+	Goroutine int            `json:"goroutine"`
+	Frames    []*ReportFrame `json:"frames"`
+	CreatedAt []*ReportFrame `json:"created_at,omitempty"`
+}
+
+// ReportFrame is a single call of a stack, see Frame.
+type ReportFrame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// ReportCoverageProfile is the coverage of a coverage profile by package, file and function, see
+// CoverProfile.
+type ReportCoverageProfile struct {
+	// Mode is the coverage mode: set, count or atomic.
+	Mode  string         `json:"mode"`
+	Total ReportCoverage `json:"total"`
+	// Packages holds the coverage of each package, sorted by name.
+	Packages []*ReportPackageCoverage `json:"packages"`
+	// Files holds the coverage of each file, sorted by name.
+	Files []*ReportFileCoverage `json:"files"`
+}
+
+// ReportCoverage is the number of statements, how many of them ran at least once, and the
+// percentage of statements covered, see Coverage.
+type ReportCoverage struct {
+	Statements int     `json:"statements"`
+	Covered    int     `json:"covered"`
+	Percent    float64 `json:"percent"`
+}
+
+// ReportPackageCoverage is the coverage of a single package.
+type ReportPackageCoverage struct {
+	Package string `json:"package"`
+	ReportCoverage
+}
+
+// ReportFileCoverage is the coverage of a single file, see FileCoverage.
+type ReportFileCoverage struct {
+	File string `json:"file"`
+	ReportCoverage
+	// Functions holds the coverage of the functions in the order they are declared. Empty if the
+	// source of the file was not available.
+	Functions []*ReportFuncCoverage `json:"functions,omitempty"`
+}
+
+// ReportFuncCoverage is the coverage of a single function, see FuncCoverage.
+type ReportFuncCoverage struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+	ReportCoverage
+}
+
+// add counts a test with the given status.
+func (c *ReportCounts) add(status Action) {
+	switch status {
+	case ActionPass:
+		c.Pass++
+	case ActionFail:
+		c.Fail++
+	case ActionSkip:
+		c.Skip++
+	case ActionIncomplete:
+		c.Incomplete++
+	}
+}
+
+// Report returns a machine-readable summary of the test run.
+func (s *GoTestSummary) Report() *Report {
+	report := &Report{
+		Version:  ReportVersion,
+		ExitCode: s.ExitCode(),
+		Packages: []*ReportPackage{},
+	}
+	for _, pkg := range s.GetSortedPackages(SortByPackageName) {
+		p := newReportPackage(pkg)
+		report.Counts.Pass += p.Counts.Pass
+		report.Counts.Fail += p.Counts.Fail
+		report.Counts.Skip += p.Counts.Skip
+		report.Counts.Incomplete += p.Counts.Incomplete
+		report.Packages = append(report.Packages, p)
+	}
+	return report
+}
+
+func newReportPackage(pkg *Package) *ReportPackage {
+	p := &ReportPackage{
+		Package:     pkg.Summary.Package,
+		Status:      pkg.Summary.Action,
+		Elapsed:     pkg.Summary.Elapsed,
+		Cached:      pkg.Cached,
+		Shuffled:    pkg.Shuffled,
+		ShuffleSeed: pkg.ShuffleSeed,
+		Cover:       pkg.Cover,
+		Coverage:    pkg.Coverage,
+		NoTestFiles: pkg.NoTestFiles,
+		NoTests:     pkg.NoTests,
+		Panic:       pkg.HasPanic,
+		DataRace:    pkg.HasDataRace || len(pkg.DataRaceTests) > 0,
+		BuildFailed: pkg.HasFailedBuildOrSetup,
+		Timeout:     pkg.Timeout != nil,
+		Output:      joinOutput(pkg.Output),
+	}
+	if p.Status == "" {
+		p.Status = ActionIncomplete
+	}
+	switch {
+	case pkg.HasPanic:
+		p.Output += joinOutput(pkg.PanicEvents)
+	case pkg.Timeout != nil:
+		p.Output += joinOutput(pkg.Timeout.Events)
+	}
+	if pkg.Timeout != nil {
+		p.TimeoutAfter = pkg.Timeout.After.Seconds()
+		for _, t := range pkg.Timeout.Running {
+			p.RunningTests = append(p.RunningTests, &ReportRunningTest{
+				Name:    t.Name,
+				Elapsed: t.Elapsed.Seconds(),
+			})
+		}
+	}
+	if pkg.FailedBuild != nil {
+		p.BuildOutput = pkg.FailedBuild.Output()
+	}
+	for _, b := range pkg.Benchmarks {
+		p.Benchmarks = append(p.Benchmarks, newReportBenchmark(b))
+	}
+	for _, r := range pkg.DataRaces {
+		p.DataRaces = append(p.DataRaces, &ReportDataRace{
+			Tests:    r.Tests,
+			Access:   newReportRaceAccess(r.Access),
+			Previous: newReportRaceAccess(r.Previous),
+		})
+	}
+	pkg.Walk(func(t *Test) bool {
+		test := newReportTest(t)
+		p.Counts.add(test.Status)
+		p.Tests = append(p.Tests, test)
+		return true
+	})
+	return p
+}
+
+func newReportTest(t *Test) *ReportTest {
+	attempts := t.Attempts()
+	test := &ReportTest{
+		Name:       t.Name,
+		Status:     t.Status(),
+		Elapsed:    t.Elapsed(),
+		Attempts:   len(attempts),
+		Flaky:      t.IsFlaky(),
+		SkipReason: t.SkipReason(),
+		Attrs:      t.Attrs,
+		Artifacts:  t.Artifacts,
+	}
+	for _, f := range t.Failures {
+		test.Failures = append(test.Failures, &ReportFailure{
+			File:    f.File,
+			Line:    f.Line,
+			Message: f.Message,
+		})
+	}
+	if (test.Status == ActionFail || test.Status == ActionIncomplete) && len(attempts) > 0 {
+		test.Output = joinOutput(attempts[len(attempts)-1].Events)
+	}
+	return test
+}
+
+func newReportBenchmark(b *Benchmark) *ReportBenchmark {
+	rb := &ReportBenchmark{
+		Name:       b.Name,
+		Procs:      b.Procs,
+		Iterations: b.Iterations,
+		NsPerOp:    b.NsPerOp,
+		MBPerSec:   b.MBPerSec,
+		Metrics:    b.Metrics,
+	}
+	if b.HasMem {
+		rb.BytesPerOp, rb.AllocsPerOp = &b.BytesPerOp, &b.AllocsPerOp
+	}
+	return rb
+}
+
+func newReportRaceAccess(a *RaceAccess) *ReportRaceAccess {
+	return &ReportRaceAccess{
+		Op:        a.Op,
+		Address:   a.Address,
+		Goroutine: a.Goroutine,
+		Frames:    newReportFrames(a.Frames),
+		CreatedAt: newReportFrames(a.CreatedAt),
+	}
+}
+
+func newReportFrames(frames []*Frame) []*ReportFrame {
+	var rf []*ReportFrame
+	for _, f := range frames {
+		rf = append(rf, &ReportFrame{Func: f.Func, File: f.File, Line: f.Line})
+	}
+	return rf
+}
+
+// NewReportCoverageProfile returns the coverage of the profile for a Report. The functions of each
+// file are found by parsing its source, returned by source for the file name, e.g.,
+// "example.com/foo/foo.go". Functions are left out for files whose source cannot be read.
+func NewReportCoverageProfile(profile *CoverProfile, source func(name string) ([]byte, error)) *ReportCoverageProfile {
+	p := &ReportCoverageProfile{
+		Mode:     profile.Mode,
+		Total:    newReportCoverage(profile.Total()),
+		Packages: []*ReportPackageCoverage{},
+		Files:    []*ReportFileCoverage{},
+	}
+	packages := profile.Packages()
+	names := make([]string, 0, len(packages))
+	for name := range packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p.Packages = append(p.Packages, &ReportPackageCoverage{
+			Package:        name,
+			ReportCoverage: newReportCoverage(packages[name]),
+		})
+	}
+	for _, f := range profile.Files {
+		file := &ReportFileCoverage{
+			File:           f.Name,
+			ReportCoverage: newReportCoverage(f.Coverage()),
+		}
+		if src, err := source(f.Name); err == nil {
+			// Best effort, the file may have changed since the profile was written.
+			funcs, _ := f.Functions(src)
+			for _, fn := range funcs {
+				file.Functions = append(file.Functions, &ReportFuncCoverage{
+					Name:           fn.Name,
+					Line:           fn.Line,
+					ReportCoverage: newReportCoverage(fn.Coverage),
+				})
+			}
+		}
+		p.Files = append(p.Files, file)
+	}
+	return p
+}
+
+func newReportCoverage(c Coverage) ReportCoverage {
+	return ReportCoverage{
+		Statements: c.Statements,
+		Covered:    c.Covered,
+		Percent:    c.Percent(),
+	}
+}
+
+// joinOutput returns the output of the events as a single string.
+func joinOutput(events []*Event) string {
+	var sb strings.Builder
+	for _, e := range events {
+		if e.Action == ActionOutput {
+			sb.WriteString(e.Output)
+		}
+	}
+	return sb.String()
+}
+
+// ReadReport reads a Report written by tparse -format json. ErrNotReport is returned if the input
+// is valid JSON, but not a Report.
+func ReadReport(r io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, err
+	}
+	switch {
+	case report.Version == 0:
+		return nil, ErrNotReport
+	case report.Version > ReportVersion:
+		return nil, fmt.Errorf("unsupported report version %d, must be %d or lower", report.Version, ReportVersion)
+	}
+	return &report, nil
+}
+
+// Summary returns a summary with the package results of the report, such as the status, elapsed
+// time, coverage, benchmarks, data races and timeout of each package. Tests are not restored, since
+// the report does not hold their events.
+func (r *Report) Summary() *GoTestSummary {
+	summary := &GoTestSummary{
+		Packages: make(map[string]*Package, len(r.Packages)),
+		Builds:   make(map[string]*Build),
+	}
+	for _, p := range r.Packages {
+		pkg := newPackage()
+		pkg.Summary = &Event{
+			Action:  p.Status,
+			Package: p.Package,
+			Elapsed: p.Elapsed,
+		}
+		pkg.Cached = p.Cached
+		pkg.Shuffled = p.Shuffled
+		pkg.ShuffleSeed = p.ShuffleSeed
+		pkg.Cover = p.Cover
+		pkg.Coverage = p.Coverage
+		pkg.NoTestFiles = p.NoTestFiles
+		pkg.NoTests = p.NoTests
+		pkg.HasPanic = p.Panic
+		pkg.HasDataRace = p.DataRace
+		pkg.HasFailedBuildOrSetup = p.BuildFailed
+		if p.Timeout {
+			pkg.Timeout = &Timeout{After: seconds(p.TimeoutAfter)}
+			for _, t := range p.RunningTests {
+				pkg.Timeout.Running = append(pkg.Timeout.Running, &RunningTest{
+					Name:    t.Name,
+					Elapsed: seconds(t.Elapsed),
+				})
+			}
+		}
+		for _, b := range p.Benchmarks {
+			pkg.Benchmarks = append(pkg.Benchmarks, b.benchmark(p.Package))
+		}
+		for _, r := range p.DataRaces {
+			pkg.DataRaces = append(pkg.DataRaces, &DataRace{
+				Tests:    r.Tests,
+				Access:   r.Access.raceAccess(),
+				Previous: r.Previous.raceAccess(),
+			})
+		}
+		summary.Packages[p.Package] = pkg
+	}
+	return summary
+}
+
+func (rb *ReportBenchmark) benchmark(pkg string) *Benchmark {
+	b := &Benchmark{
+		Name:       rb.Name,
+		Package:    pkg,
+		Procs:      rb.Procs,
+		Iterations: rb.Iterations,
+		NsPerOp:    rb.NsPerOp,
+		MBPerSec:   rb.MBPerSec,
+		Metrics:    rb.Metrics,
+	}
+	if rb.BytesPerOp != nil && rb.AllocsPerOp != nil {
+		b.BytesPerOp, b.AllocsPerOp, b.HasMem = *rb.BytesPerOp, *rb.AllocsPerOp, true
+	}
+	return b
+}
+
+func (ra *ReportRaceAccess) raceAccess() *RaceAccess {
+	if ra == nil {
+		return nil
+	}
+	return &RaceAccess{
+		Op:        ra.Op,
+		Address:   ra.Address,
+		Goroutine: ra.Goroutine,
+		Frames:    frames(ra.Frames),
+		CreatedAt: frames(ra.CreatedAt),
+	}
+}
+
+func frames(rf []*ReportFrame) []*Frame {
+	var fs []*Frame
+	for _, f := range rf {
+		fs = append(fs, &Frame{Func: f.Func, File: f.File, Line: f.Line})
+	}
+	return fs
+}
+
+// seconds converts a duration in seconds, as reported by the report, to a time.Duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,68 @@
+package parse
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// shuffleRe matches the line go test prints before running the tests of a package with -shuffle,
+// e.g., "-test.shuffle 1698765".
+var shuffleRe = regexp.MustCompile(`^-test\.shuffle (-?[0-9]+)$`)
+
+const shufflePrefix = "-test.shuffle "
+
+// Shuffle reports the seed the tests of a package were shuffled with, see go test -shuffle:
+// "-test.shuffle 1698765\n"
+func (e *Event) Shuffle() (int64, bool) {
+	// Cheap check first, this is called for every output event.
+	if !strings.HasPrefix(e.Output, shufflePrefix) {
+		return 0, false
+	}
+	ss := shuffleRe.FindStringSubmatch(strings.TrimSuffix(e.Output, "\n"))
+	if ss == nil {
+		return 0, false
+	}
+	seed, err := strconv.ParseInt(ss[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return seed, true
+}
+
+// ShuffleCommand returns the go test command that runs the tests of the package in the same order
+// again, to reproduce a failure that depends on the order of the tests. An empty string is
+// returned if the tests were not shuffled.
+//
+// Tests are shuffled before -run selects which of them run, so a subset of the tests runs in the
+// same order too. The command runs the top-level tests up to and including the last one that
+// failed, since any test that ran before a failed test may have caused it to fail, e.g., by
+// changing shared state:
+//
+//	go test -shuffle=1698765 -run='^(TestB|TestA|TestC)$' example.com/foo
+func (p *Package) ShuffleCommand() string {
+	if !p.Shuffled {
+		return ""
+	}
+	cmd := "go test -shuffle=" + strconv.FormatInt(p.ShuffleSeed, 10)
+	var names []string
+	var n int
+	for _, t := range p.Root() {
+		// Benchmarks only run with -bench, after the tests.
+		if isBenchmark(t.Name) {
+			continue
+		}
+		// Top-level test names are Go identifiers, there is nothing to quote.
+		names = append(names, t.Name)
+		if status := t.Status(); status == ActionFail || status == ActionIncomplete {
+			n = len(names)
+		}
+	}
+	if n > 0 {
+		cmd += " -run='^(" + strings.Join(names[:n], "|") + ")$'"
+	}
+	if p.Summary.Package != "" {
+		cmd += " " + p.Summary.Package
+	}
+	return cmd
+}
@@ -0,0 +1,22 @@
+package parse
+
+// SkipReason returns the message the test was skipped with, e.g., "needs docker" for
+// t.Skip("needs docker"), or an empty string if the test was not skipped. Lines of a multi-line
+// message are separated by "\n".
+//
+// A test skipped without a message, e.g., with t.SkipNow, reports the reason of the nearest
+// skipped parent test, if any. Since t.Skip is t.Log followed by t.SkipNow, a test that logged a
+// message before calling t.SkipNow reports that message.
+func (t *Test) SkipReason() string {
+	for ; t != nil; t = t.Parent {
+		if t.Status() != ActionSkip {
+			return ""
+		}
+		attempts := t.Attempts()
+		messages := parseMessages(attempts[len(attempts)-1].Events, func(*Event) bool { return true })
+		if len(messages) > 0 {
+			return messages[len(messages)-1].Message
+		}
+	}
+	return ""
+}
@@ -0,0 +1,264 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+	"time"
+)
+
+// Stream parses go test output incrementally. Unlike Process, which blocks until EOF, a Stream
+// yields each event as soon as it's parsed and exposes the summary built so far.
+//
+// A Stream is not safe for concurrent use. The summary must not be read by another goroutine
+// while the events are being consumed.
+type Stream struct {
+	r       io.Reader
+	option  *options
+	summary *GoTestSummary
+}
+
+// StreamEvent is a single parsed event, along with the test or package it completed, if any.
+type StreamEvent struct {
+	// Event is the parsed event. This includes events that do not contribute to the summary, such
+	// as "=== RUN" output.
+	Event *Event
+	// Test is set when the event reports the outcome of a test: pass, fail or skip.
+	Test *Test
+	// Package is set when the event is the final event of a package, i.e., the package summary.
+	Package *Package
+}
+
+// NewStream returns a Stream that reads go test output in JSON format, or plain text, from r. It accepts the
+// same options as Process.
+func NewStream(r io.Reader, optionsFunc ...OptionsFunc) *Stream {
+	option := &options{}
+	for _, f := range optionsFunc {
+		f(option)
+	}
+	return &Stream{
+		r:      r,
+		option: option,
+		summary: &GoTestSummary{
+			Packages: make(map[string]*Package),
+		},
+	}
+}
+
+// Summary returns the summary of all events consumed so far. The summary is updated in place as
+// more events are consumed, and is complete once Events has been fully consumed.
+func (s *Stream) Summary() *GoTestSummary {
+	return s.summary
+}
+
+// Events returns an iterator over the parsed events. The iteration stops at EOF or at the first
+// error, which is yielded with a nil StreamEvent. If the input contains no go test JSON output at
+// all, ErrNotParsable is yielded.
+//
+// Plain text go test output, e.g., go test -v without -json, is converted to events as well.
+// Since plain text output only names a package once it has finished, the events of a package are
+// yielded together at the end of the package.
+//
+// Events reads from the underlying reader and can only be consumed once.
+func (s *Stream) Events() iter.Seq2[*StreamEvent, error] {
+	return func(yield func(*StreamEvent, error) bool) {
+		option, summary := s.option, s.summary
+
+		lr := newLineReader(s.r, option.maxLineSize)
+		var started bool
+		var badLines int
+		// text is set once the input is detected as plain text go test output, i.e., go test was
+		// run without -json.
+		var text *textConverter
+		for {
+			line, dropped, err := lr.next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				yield(nil, fmt.Errorf("received scanning error: %w", err))
+				return
+			}
+			if text == nil && !started && isTextOutput(string(line)) {
+				text = &textConverter{}
+				started = true
+			}
+			if text != nil {
+				output := string(line)
+				if dropped > 0 {
+					output += truncatedMarker(dropped)
+				}
+				events, raw := text.convert(output)
+				if raw {
+					summary.AddRawEvent(output)
+					if option.follow && option.w != nil {
+						fmt.Fprintf(option.w, "%s\n", output)
+					}
+				}
+				for _, e := range events {
+					if !yield(s.process(e), nil) {
+						return
+					}
+				}
+				continue
+			}
+			if dropped > 0 {
+				// A single line exceeded the maximum line size, typically a test that logged a
+				// large blob. Keep the beginning of the output rather than failing the whole run.
+				e, err := newTruncatedEvent(line, dropped)
+				if err == nil {
+					started = true
+					if !yield(s.process(e), nil) {
+						return
+					}
+					continue
+				}
+				if started {
+					if option.debug {
+						fmt.Fprintf(os.Stderr, "debug: skipping line of %d bytes: %v\n", len(line)+dropped, err)
+					}
+					continue
+				}
+				// Before the first event, the line may as well not be go test output at all, count
+				// it as a bad line below.
+			}
+			// Scan up-to 50 lines for a parsable event, if we get one, expect
+			// no errors to follow until EOF.
+			e, err := NewEvent(line)
+			if err != nil {
+				// We failed to parse a go test JSON event, but there are special cases for failed
+				// builds, setup, etc. Let special case these and bubble them up in the summary
+				// if the output belongs to a package.
+				summary.AddRawEvent(string(line))
+
+				badLines++
+				if started || badLines > 50 {
+					var syntaxError *json.SyntaxError
+					if errors.As(err, &syntaxError) {
+						err = fmt.Errorf("line %d JSON error: %s: %w", badLines, syntaxError.Error(), ErrNotParsable)
+						if option.debug {
+							// In debug mode we can surface a more verbose error message which
+							// contains the current line number and exact JSON parsing error.
+							fmt.Fprintf(os.Stderr, "debug: %s", err.Error())
+						}
+					}
+					yield(nil, err)
+					return
+				}
+				if option.follow && option.w != nil {
+					fmt.Fprintf(option.w, "%s\n", line)
+				}
+				continue
+			}
+			started = true
+
+			if !yield(s.process(e), nil) {
+				return
+			}
+		}
+		if text != nil {
+			for _, e := range text.flush() {
+				if !yield(s.process(e), nil) {
+					return
+				}
+			}
+		}
+		// Entire input has been scanned and no go test output was found.
+		if !started {
+			yield(nil, ErrNotParsable)
+		}
+	}
+}
+
+// process adds a single parsed event to the summary, and reports the test or package the event
+// completed, if any.
+func (s *Stream) process(e *Event) *StreamEvent {
+	option, summary := s.option, s.summary
+	se := &StreamEvent{Event: e}
+
+	// TODO(mf): when running tparse locally it's very useful to see progress for long-running
+	// test suites. Since we have access to the event we can send it on a chan
+	// or just directly update a spinner-like component. This cannot be run with the
+	// follow option. Lastly, need to consider what local vs CI behavior would be like.
+	// Depending on how often the frames update, this could cause a lot of noise, so maybe
+	// we need to expose an interval option, so in CI it would update infrequently.
+
+	// Optionally, as test output is piped to us, we write the plain
+	// text Output as if go test was run without the -json flag.
+	if (option.follow || option.followVerbose) && option.w != nil {
+		if !option.followVerbose && isNoisy(e) {
+			return se
+		}
+		if e.Output != "" && option.includeTimestamp {
+			fmt.Fprint(option.w, e.Time.Format(time.RFC3339)+" "+e.Output)
+		} else {
+			fmt.Fprint(option.w, e.Output)
+		}
+
+	}
+	// Progress is a special case of follow, where we only print the
+	// progress of the test suite, but not the output.
+	if option.progress && option.w != nil {
+		printProgress(option.progressOutput, e, summary.Packages)
+	}
+
+	// Build output is grouped by package and kept out of test events. Not all build output is
+	// an error, there is a class of build output that is benign, like:
+	// https://github.com/golang/go/issues/61229
+	//
+	//  Example:
+	//  ld: warning: '.../go.o' has malformed LC_DYSYMTAB, expected 92 undefined symbols to start at index 15983, found 102 undefined symbol
+	//
+	// Callers can tell the two apart with Build.Failed.
+	if be, ok := e.BuildEvent(); ok {
+		summary.addBuildEvent(be)
+		return se
+	}
+
+	summary.AddEvent(e)
+
+	if pkg, ok := summary.Packages[e.Package]; ok {
+		switch {
+		case e.LastLine():
+			se.Package = pkg
+		case e.Test != "" && (e.Action == ActionPass || e.Action == ActionFail || e.Action == ActionSkip):
+			se.Test = pkg.GetTest(e.Test)
+		}
+	}
+	return se
+}
+
+var noisy = []string{
+	// 1. Filter out noisy output, such as === RUN, === PAUSE, etc.
+	updatePrefixRun,
+	updatePrefixPause,
+	updatePrefixCont,
+	updatePrefixPass,
+	updatePrefixSkip,
+	// 2. Filter out report output, such as --- PASS: and --- SKIP:
+	resultPrefixPass,
+	resultPrefixSkip,
+}
+
+// isNoisy reports whether the event is noise when following go test output.
+func isNoisy(e *Event) bool {
+	output := strings.TrimSpace(e.Output)
+	// If the event is a big pass or fail, we can safely discard it. These are typically the
+	// lines preceding the package summary line. For example:
+	//
+	//  PASS
+	//  ok      fmt 0.144s
+	if e.Test == "" && (output == bigPass || output == bigFail) {
+		return true
+	}
+	for _, prefix := range noisy {
+		if strings.HasPrefix(output, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,49 +2,275 @@ package parse
 
 import (
 	"sort"
+	"strings"
 )
 
 // Test represents a single, unique, package test.
 type Test struct {
 	Name    string
 	Package string
-	Events  []*Event
+	// Events holds the events of the test in the order they were received. Events must only be
+	// appended to, the values derived from them, such as Status and Elapsed, are computed
+	// incrementally.
+	Events []*Event
+
+	// Parent is the test that started this test with t.Run, or nil if this is a top-level test.
+	Parent *Test
+	// Children holds the subtests started by this test, in the order they were first seen.
+	Children []*Test
+
+	// Fuzz describes the failing input of a fuzz test. Only set on a failed top-level fuzz test,
+	// e.g., FuzzFoo, and nil otherwise.
+	Fuzz *FuzzFailure
+
+	// Example holds the actual and expected output of a failed Example test, and nil otherwise.
+	Example *ExampleFailure
+
+	// Failures holds the failures reported by the test, in the order they were reported. Only set
+	// on failed tests. If the test ran more than once, the failures are those of the last failed
+	// attempt.
+	Failures []*Failure
+
+	// Assertions holds the testify and go-cmp assertions that failed, in the order they were
+	// reported. Only set on failed tests. If the test ran more than once, the assertions are those
+	// of the last failed attempt.
+	Assertions []*Assertion
+
+	// DataRaces holds the data races triggered by the test, see Package.DataRaces.
+	DataRaces []*DataRace
+
+	// Attrs holds the attributes the test set with t.Attr, keyed by name. Since go1.25. See Attr
+	// for attributes inherited from parent tests.
+	Attrs map[string]string
+
+	// Artifacts holds the directories the test stored output files in, see t.ArtifactDir and
+	// go test -artifacts, in the order they were reported. Since go1.26.
+	Artifacts []string
+
+	cache testCache
+}
+
+// Attempt is a single run of a test. A test run with go test -count=N has one attempt per run,
+// each with its own outcome.
+type Attempt struct {
+	// Events holds the events of the run, in the order they were received.
+	Events []*Event
+	// Status is the outcome of the run: pass, fail or skip. Empty if the run has not finished.
+	Status Action
+	// Elapsed is how long the run took, in seconds.
+	Elapsed float64
+
+	hasRunEvent bool
+}
+
+// Failures returns the failures reported by the run, see Test.Failures.
+func (a *Attempt) Failures() []*Failure {
+	return parseFailures(a.Events)
+}
+
+// testCache holds values derived from the events of a test, so they don't have to be recomputed
+// from all events on every call.
+type testCache struct {
+	// n is the number of events scanned so far.
+	n int
+	// status is the last terminal action seen: pass, fail or skip. Empty if there is none yet.
+	status  Action
+	elapsed float64
+	// failed reports whether any attempt failed.
+	failed   bool
+	attempts []*Attempt
+}
+
+// scan updates the cache with events received since the last call.
+func (t *Test) scan() {
+	if t.cache.n > len(t.Events) {
+		// Events were replaced rather than appended to, start over.
+		t.cache = testCache{}
+	}
+	for _, e := range t.Events[t.cache.n:] {
+		// Every run of the test starts with a run event.
+		if n := len(t.cache.attempts); n == 0 || (e.Action == ActionRun && t.cache.attempts[n-1].started()) {
+			t.cache.attempts = append(t.cache.attempts, &Attempt{})
+		}
+		attempt := t.cache.attempts[len(t.cache.attempts)-1]
+		attempt.Events = append(attempt.Events, e)
+		switch e.Action {
+		case ActionRun:
+			attempt.hasRunEvent = true
+		case ActionPass, ActionSkip, ActionFail:
+			t.cache.status = e.Action
+			attempt.Status = e.Action
+		case ActionBench:
+			t.cache.status = ActionPass
+			attempt.Status = ActionPass
+		}
+		if e.Action == ActionFail {
+			t.cache.failed = true
+		}
+		if e.Elapsed > t.cache.elapsed {
+			t.cache.elapsed = e.Elapsed
+		}
+		if e.Elapsed > attempt.Elapsed {
+			attempt.Elapsed = e.Elapsed
+		}
+	}
+	t.cache.n = len(t.Events)
+}
+
+// started reports whether the attempt has a run event or has finished.
+func (a *Attempt) started() bool {
+	return a.hasRunEvent || a.Status != ""
 }
 
 // Elapsed indicates how long a given test ran (in seconds), by scanning for the largest
 // elapsed value from all events.
 func (t *Test) Elapsed() float64 {
-	var f float64
-	for _, e := range t.Events {
-		if e.Elapsed > f {
-			f = e.Elapsed
+	t.scan()
+	return t.cache.elapsed
+}
+
+// Status reports the outcome of the test represented as a single Action: pass, fail, skip or
+// incomplete. The outcome is the last pass, fail or skip event received, unless the test ran more
+// than once and any attempt failed, in which case the test failed.
+//
+// Tests that ran but never finished, e.g., because the test binary crashed, was killed or the
+// output was truncated, are reported as incomplete rather than failed. Benchmarks that did not fail
+// or skip are reported as pass, because go test does not emit a pass event for a successful
+// benchmark.
+func (t *Test) Status() Action {
+	t.scan()
+	if t.cache.failed {
+		return ActionFail
+	}
+	if isBenchmark(t.Name) {
+		if t.cache.status != "" {
+			return t.cache.status
 		}
+		return ActionPass
 	}
-	return f
+	if n := len(t.cache.attempts); n == 0 || t.cache.attempts[n-1].Status == "" {
+		// The last attempt never finished.
+		return ActionIncomplete
+	}
+	return t.cache.status
 }
 
-// Status reports the outcome of the test represented as a single Action: pass, fail or skip.
-func (t *Test) Status() Action {
-	// sort by time and scan for an action in reverse order.
-	// The first action we come across (in reverse order) is
-	// the outcome of the test, which will be one of pass|fail|skip.
-	t.SortEvents()
-	for i := len(t.Events) - 1; i >= 0; i-- {
-		switch t.Events[i].Action {
+// Attempts returns each run of the test in the order they started. Tests run once, the default,
+// have a single attempt.
+func (t *Test) Attempts() []*Attempt {
+	t.scan()
+	return t.cache.attempts
+}
+
+// AttemptsByStatus returns the attempts of the test with the given outcome: pass, fail or skip.
+func (t *Test) AttemptsByStatus(action Action) []*Attempt {
+	var attempts []*Attempt
+	for _, a := range t.Attempts() {
+		if a.Status == action {
+			attempts = append(attempts, a)
+		}
+	}
+	return attempts
+}
+
+// IsFlaky reports whether the test ran more than once with different outcomes, i.e., at least
+// one attempt passed and at least one failed.
+func (t *Test) IsFlaky() bool {
+	var passed, failed bool
+	for _, a := range t.Attempts() {
+		switch a.Status {
 		case ActionPass:
-			return ActionPass
-		case ActionSkip:
-			return ActionSkip
+			passed = true
 		case ActionFail:
-			return ActionFail
+			failed = true
 		}
 	}
-	return ActionFail
+	return passed && failed
 }
 
-// SortEvents sorts test events by elapsed time in ascending order, i.e., oldest to newest.
+// SortEvents sorts test events by elapsed time in ascending order, i.e., oldest to newest. Events
+// with the same time, such as cached results which have no time, keep the order they were
+// received in.
+//
+// Events are already received in order, so this is rarely needed.
 func (t *Test) SortEvents() {
-	sort.Slice(t.Events, func(i, j int) bool {
+	sort.SliceStable(t.Events, func(i, j int) bool {
 		return t.Events[i].Time.Before(t.Events[j].Time)
 	})
+	t.cache = testCache{}
+}
+
+// IsBenchmark reports whether the test is a benchmark, e.g., BenchmarkFoo or one of its
+// sub-benchmarks.
+func (t *Test) IsBenchmark() bool {
+	return isBenchmark(t.Name)
+}
+
+// IsSubtest reports whether the test was started by another test with t.Run.
+func (t *Test) IsSubtest() bool {
+	return t.Parent != nil
+}
+
+// Depth reports how deeply nested the test is. Top-level tests have a depth of 0, their direct
+// subtests a depth of 1, and so on.
+func (t *Test) Depth() int {
+	var n int
+	for p := t.Parent; p != nil; p = p.Parent {
+		n++
+	}
+	return n
+}
+
+// ShortName returns the test name relative to its parent. For example, the subtest
+// "TestA/sub/case" with parent "TestA/sub" returns "case". Top-level tests return the full name.
+func (t *Test) ShortName() string {
+	if t.Parent == nil {
+		return t.Name
+	}
+	return strings.TrimPrefix(t.Name, t.Parent.Name+"/")
+}
+
+// Walk calls fn for the test and each of its subtests, depth-first, in the order the subtests were
+// first seen. If fn returns false, the subtests of that test are not visited.
+func (t *Test) Walk(fn func(*Test) bool) {
+	if !fn(t) {
+		return
+	}
+	for _, child := range t.Children {
+		child.Walk(fn)
+	}
+}
+
+// IsPropagatedFailure reports whether the test failed only because one or more of its subtests
+// failed. This is the case when go test marks a parent as failed without the parent itself
+// producing any output besides its own "--- FAIL" line.
+//
+// Leaf failures, and parents that failed on their own (e.g., calling t.Error after t.Run),
+// return false.
+func (t *Test) IsPropagatedFailure() bool {
+	if t.Status() != ActionFail {
+		return false
+	}
+	var failedChild bool
+	for _, child := range t.Children {
+		if child.Status() == ActionFail {
+			failedChild = true
+			break
+		}
+	}
+	if !failedChild {
+		return false
+	}
+	for _, e := range t.Events {
+		if e.Action != ActionOutput {
+			continue
+		}
+		output := strings.TrimSpace(e.Output)
+		// Ignore the result line and update lines such as "=== RUN" or "=== NAME".
+		if output == "" || strings.HasPrefix(output, resultPrefixFail) || strings.HasPrefix(output, "=== ") {
+			continue
+		}
+		return false
+	}
+	return true
 }
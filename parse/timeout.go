@@ -0,0 +1,80 @@
+package parse
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Timeout describes a test binary that was stopped because it ran longer than the go test -timeout
+// flag allows. go test reports the tests that were still running, followed by a goroutine dump:
+//
+//	panic: test timed out after 10m0s
+//		running tests:
+//			TestFoo (10m0s)
+//			TestFoo/bar (9m58s)
+//
+//	goroutine 9 [running]:
+//	...
+type Timeout struct {
+	// After is the timeout that was exceeded, e.g., 10m0s.
+	After time.Duration
+	// Running holds the tests that were still running when the timeout was exceeded, in the order
+	// they were reported.
+	Running []*RunningTest
+	// Events holds the output events from the "panic: test timed out" line onwards.
+	Events []*Event
+}
+
+// RunningTest is a test that was still running when the test binary timed out.
+type RunningTest struct {
+	Name string
+	// Elapsed is how long the test had been running, or 0 if unknown.
+	Elapsed time.Duration
+}
+
+const timeoutPrefix = "panic: test timed out after "
+
+var runningTestRe = regexp.MustCompile(`^\t\t(\S+) \(([^)]+)\)$`)
+
+// IsTimeout indicates the test binary timed out, see Timeout.
+func (e *Event) IsTimeout() bool {
+	return strings.HasPrefix(e.Output, timeoutPrefix)
+}
+
+// addTimeoutEvent adds an event that follows the timeout, and collects the running tests.
+func (p *Package) addTimeoutEvent(e *Event) {
+	if e.IsTimeout() {
+		after := strings.TrimSpace(strings.TrimPrefix(e.Output, timeoutPrefix))
+		p.Timeout = &Timeout{}
+		p.Timeout.After, _ = time.ParseDuration(after)
+	}
+	p.Timeout.Events = append(p.Timeout.Events, e)
+	if ss := runningTestRe.FindStringSubmatch(strings.TrimSuffix(e.Output, "\n")); ss != nil {
+		elapsed, _ := time.ParseDuration(ss[2])
+		p.Timeout.Running = append(p.Timeout.Running, &RunningTest{Name: ss[1], Elapsed: elapsed})
+	}
+	if e.LastLine() && len(p.Timeout.Running) == 0 {
+		// Before go1.20 the running tests are not reported. Tests that started or continued
+		// running, but did not finish, were running.
+		for _, t := range p.Tests {
+			if t.running() {
+				p.Timeout.Running = append(p.Timeout.Running, &RunningTest{Name: t.Name})
+			}
+		}
+	}
+}
+
+// running reports whether the test is running, i.e., it started or continued running and did not
+// finish or pause.
+func (t *Test) running() bool {
+	for i := len(t.Events) - 1; i >= 0; i-- {
+		switch t.Events[i].Action {
+		case ActionRun, ActionCont:
+			return true
+		case ActionPause, ActionPass, ActionFail, ActionSkip:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package parse
+
+import (
+	"sort"
+	"time"
+)
+
+// Timing describes when a test ran, derived from the times of its run, pause, cont and final
+// events. A test that calls t.Parallel is paused until its parent test returns and a parallel slot
+// is available, see go test -parallel, which is time spent waiting rather than running.
+//
+// Tests run with go test -count=N add up the time of all attempts.
+type Timing struct {
+	// Start is the time of the first event of the test, and End the time of the last event.
+	Start, End time.Time
+	// Running is how long the test ran, and Paused how long it waited to continue running.
+	Running time.Duration
+	Paused  time.Duration
+	// Runs holds the periods the test ran, in the order they started. Running is their total.
+	Runs []Period
+}
+
+// Period is a period of time between Start and End.
+type Period struct {
+	Start, End time.Time
+}
+
+// Timing returns when the test ran and for how long. The zero value is returned if the events have
+// no times, e.g., when the output was converted from go test output without -json.
+func (t *Test) Timing() Timing {
+	var timing Timing
+	// The start of the current period, either running or paused.
+	var since time.Time
+	var paused bool
+	for _, e := range t.Events {
+		if e.Time.IsZero() {
+			continue
+		}
+		if timing.Start.IsZero() {
+			timing.Start = e.Time
+		}
+		timing.End = e.Time
+		switch e.Action {
+		case ActionRun, ActionCont:
+			if !since.IsZero() && paused {
+				timing.Paused += e.Time.Sub(since)
+			}
+			since, paused = e.Time, false
+		case ActionPause:
+			if !since.IsZero() && !paused {
+				timing.add(since, e.Time)
+			}
+			since, paused = e.Time, true
+		case ActionPass, ActionFail, ActionSkip:
+			if !since.IsZero() && !paused {
+				timing.add(since, e.Time)
+			}
+			since = time.Time{}
+		}
+	}
+	if !since.IsZero() && !paused {
+		// The test never finished, it ran up to its last event.
+		timing.add(since, timing.End)
+	}
+	return timing
+}
+
+func (t *Timing) add(start, end time.Time) {
+	t.Running += end.Sub(start)
+	t.Runs = append(t.Runs, Period{Start: start, End: end})
+}
+
+// IsParallel reports whether the test called t.Parallel, i.e., it was paused at least once.
+func (t *Test) IsParallel() bool {
+	for _, e := range t.Events {
+		if e.Action == ActionPause {
+			return true
+		}
+	}
+	return false
+}
+
+// PeakConcurrency returns the largest number of tests of the package that ran at the same time.
+// Only tests without subtests are counted, since a test that waits for its subtests to finish is
+// not running itself.
+func (p *Package) PeakConcurrency() int {
+	type change struct {
+		at    time.Time
+		delta int
+	}
+	var changes []change
+	for _, t := range p.Tests {
+		if len(t.Children) > 0 {
+			continue
+		}
+		for _, r := range t.Timing().Runs {
+			changes = append(changes, change{at: r.Start, delta: 1}, change{at: r.End, delta: -1})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if !changes[i].at.Equal(changes[j].at) {
+			return changes[i].at.Before(changes[j].at)
+		}
+		// A test that finishes at the same time another one starts did not run alongside it.
+		return changes[i].delta < changes[j].delta
+	})
+	var running, peak int
+	for _, c := range changes {
+		running += c.delta
+		peak = max(peak, running)
+	}
+	return peak
+}
@@ -0,0 +1,103 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestAssertions(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(filepath.Join("testdata", "assertion", "test_01.jsonl"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	summary, err := parse.Process(f)
+	require.NoError(t, err)
+	pkg, ok := summary.Packages["example.com/fx2/assert"]
+	require.True(t, ok)
+
+	t.Run("expected_actual", func(t *testing.T) {
+		test := pkg.GetTest("TestEqualInt")
+		require.NotNil(t, test)
+		require.Len(t, test.Assertions, 1)
+		a := test.Assertions[0]
+		assert.Equal(t, "assert_test.go", a.File)
+		assert.Equal(t, 18, a.Line)
+		assert.Equal(t, "Not equal:", a.Message)
+		assert.Equal(t, "1", a.Expected)
+		assert.Equal(t, "2", a.Actual)
+		assert.NotEmpty(t, a.Events)
+	})
+	t.Run("diff", func(t *testing.T) {
+		test := pkg.GetTest("TestSubtest/strings")
+		require.NotNil(t, test)
+		require.Len(t, test.Assertions, 1)
+		a := test.Assertions[0]
+		assert.Equal(t, 35, a.Line)
+		assert.Equal(t, []parse.DiffLine{
+			{Op: parse.DiffEqual, Text: "line one"},
+			{Op: parse.DiffRemoved, Text: "line two"},
+			{Op: parse.DiffAdded, Text: "line 2"},
+			{Op: parse.DiffEqual, Text: "line three"},
+		}, a.Diff)
+	})
+	t.Run("messages", func(t *testing.T) {
+		test := pkg.GetTest("TestEqualStruct")
+		require.NotNil(t, test)
+		require.Len(t, test.Assertions, 1)
+		assert.Equal(t, "user alice", test.Assertions[0].Messages)
+		assert.NotEmpty(t, test.Assertions[0].Diff)
+	})
+	t.Run("multiple", func(t *testing.T) {
+		test := pkg.GetTest("TestMultiple")
+		require.NotNil(t, test)
+		require.Len(t, test.Assertions, 2)
+		assert.Equal(t, 28, test.Assertions[0].Line)
+		assert.Equal(t, 29, test.Assertions[1].Line)
+	})
+	t.Run("cmp", func(t *testing.T) {
+		test := pkg.GetTest("TestCmp")
+		require.NotNil(t, test)
+		require.Len(t, test.Assertions, 1)
+		a := test.Assertions[0]
+		assert.Equal(t, 43, a.Line)
+		assert.Equal(t, "user mismatch (-want +got):", a.Message)
+		assert.Contains(t, a.Diff, parse.DiffLine{Op: parse.DiffRemoved, Text: "\tEmail: \"alice@example.com\","})
+		assert.Contains(t, a.Diff, parse.DiffLine{Op: parse.DiffAdded, Text: "\tEmail: \"bob@example.com\","})
+	})
+	t.Run("plain", func(t *testing.T) {
+		test := pkg.GetTest("TestPlain")
+		require.NotNil(t, test)
+		assert.Empty(t, test.Assertions)
+	})
+}
+
+func TestAssertionsTable(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.NewBuffer(nil)
+	inputFile := filepath.Join("testdata", "assertion", "test_01.jsonl")
+	options := app.Options{
+		FileName:     inputFile,
+		DisableColor: true,
+		Output:       buf,
+		Sorter:       parse.SortByPackageName,
+	}
+	gotExitCode, err := app.Run(options)
+	require.NoError(t, err)
+	assert.Equal(t, 1, gotExitCode)
+
+	goldenFile := filepath.Join("testdata", "assertion", "test_01.golden")
+	want, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+}
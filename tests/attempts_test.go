@@ -0,0 +1,109 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestAttempts(t *testing.T) {
+	t.Parallel()
+
+	// go test -json -count=5 ./flaky
+	//
+	// TestFlaky fails every other run, TestStable always passes and TestBroken always fails.
+	inputFile := filepath.Join("testdata", "attempts", "test_01.jsonl")
+
+	t.Run("parse", func(t *testing.T) {
+		t.Parallel()
+		f, err := os.Open(inputFile)
+		require.NoError(t, err)
+		defer f.Close()
+		summary, err := parse.Process(f)
+		require.NoError(t, err)
+		pkg := summary.Packages["example.com/fx/flaky"]
+		require.NotNil(t, pkg)
+
+		tt := []struct {
+			name   string
+			status parse.Action
+			passed int
+			flaky  bool
+		}{
+			{"TestFlaky", parse.ActionFail, 3, true},
+			{"TestStable", parse.ActionPass, 5, false},
+			{"TestBroken", parse.ActionFail, 0, false},
+			{"TestBroken/sub", parse.ActionFail, 0, false},
+		}
+		for _, tc := range tt {
+			test := pkg.GetTest(tc.name)
+			require.NotNil(t, test, tc.name)
+			// The last attempt of TestFlaky passed, but the test is still reported as failed.
+			assert.Equal(t, tc.status, test.Status(), tc.name)
+			require.Len(t, test.Attempts(), 5, tc.name)
+			assert.Len(t, test.AttemptsByStatus(parse.ActionPass), tc.passed, tc.name)
+			assert.Equal(t, tc.flaky, test.IsFlaky(), tc.name)
+			for _, a := range test.Attempts() {
+				require.NotEmpty(t, a.Events)
+				assert.Equal(t, parse.ActionRun, a.Events[0].Action, tc.name)
+				assert.NotEmpty(t, a.Status, tc.name)
+			}
+		}
+		// Each attempt holds its own output.
+		flaky := pkg.GetTest("TestFlaky")
+		failed := flaky.AttemptsByStatus(parse.ActionFail)
+		require.Len(t, failed, 2)
+		assert.Contains(t, outputOf(failed[0].Events), "run 2 failed")
+		assert.Contains(t, outputOf(failed[1].Events), "run 4 failed")
+		assert.NotContains(t, outputOf(flaky.AttemptsByStatus(parse.ActionPass)[0].Events), "failed")
+	})
+	t.Run("single", func(t *testing.T) {
+		t.Parallel()
+		f, err := os.Open(filepath.Join("testdata", "failed", "test_01.jsonl"))
+		require.NoError(t, err)
+		defer f.Close()
+		summary, err := parse.Process(f)
+		require.NoError(t, err)
+		for _, pkg := range summary.Packages {
+			for _, test := range pkg.Tests {
+				require.Len(t, test.Attempts(), 1, test.Name)
+				assert.Equal(t, test.Status(), test.Attempts()[0].Status, test.Name)
+				assert.False(t, test.IsFlaky(), test.Name)
+			}
+		}
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		exitCode, err := app.Run(app.Options{
+			FileName: inputFile,
+			Output:   buf,
+			Sorter:   parse.SortByPackageName,
+			TestTableOptions: app.TestTableOptions{
+				Pass: true,
+				Skip: true,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, exitCode)
+		goldenFile := filepath.Join("testdata", "attempts", "test_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+}
+
+func outputOf(events []*parse.Event) string {
+	var s string
+	for _, e := range events {
+		s += e.Output
+	}
+	return s
+}
@@ -0,0 +1,140 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestAttrs(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "attrs")
+
+	// go test -json -artifacts -outputdir /tmp/art, and the same without -json.
+	for _, name := range []string{"test_01.jsonl", "test_02.txt"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			pkg := processPackage(t, filepath.Join(base, name), "example.com/fx/attrs")
+			tests := make(map[string]*parse.Test)
+			for _, test := range pkg.Tests {
+				tests[test.Name] = test
+			}
+			require.Len(t, tests, 5)
+
+			assert.Equal(t, map[string]string{"owner": "alice", "suite": "auth"}, tests["TestLogin"].Attrs)
+			// Subtests inherit the attributes of their parent.
+			expired := tests["TestLogout/expired"]
+			assert.Equal(t, map[string]string{"flaky": "true"}, expired.Attrs)
+			owner, ok := expired.Attr("owner")
+			assert.True(t, ok)
+			assert.Equal(t, "alice", owner)
+			_, ok = tests["TestNoOwner"].Attr("owner")
+			assert.False(t, ok)
+
+			query := tests["TestQuery"]
+			assert.Equal(t, parse.ActionFail, query.Status())
+			require.Len(t, query.Artifacts, 1)
+			assert.True(t, strings.HasPrefix(query.Artifacts[0], "/tmp/art/_artifacts/attrs/TestQuery/"))
+			assert.Empty(t, tests["TestLogin"].Artifacts)
+			// The "=== ATTR" and "=== ARTIFACTS" lines are not part of the failure output.
+			require.Len(t, query.Failures, 1)
+			assert.Equal(t, "got 2 rows, want 1", query.Failures[0].Message)
+		})
+	}
+	t.Run("merge", func(t *testing.T) {
+		t.Parallel()
+		var summaries []*parse.GoTestSummary
+		for _, name := range []string{"test_01.jsonl", "test_02.txt"} {
+			f, err := os.Open(filepath.Join(base, name))
+			require.NoError(t, err)
+			summary, err := parse.Process(f)
+			f.Close()
+			require.NoError(t, err)
+			summaries = append(summaries, summary)
+		}
+		pkg := parse.Merge(summaries...).Packages["example.com/fx/attrs"]
+		require.NotNil(t, pkg)
+		query := pkg.GetTest("TestQuery")
+		require.NotNil(t, query)
+		assert.Equal(t, map[string]string{"owner": "bob", "suite": "db"}, query.Attrs)
+		assert.Len(t, query.Artifacts, 1)
+	})
+	t.Run("filter", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName:     filepath.Join(base, "test_01.jsonl"),
+			Output:       buf,
+			DisableColor: true,
+			Sorter:       parse.SortByPackageName,
+			TestTableOptions: app.TestTableOptions{
+				Pass:  true,
+				Attrs: map[string]string{"owner": "alice"},
+			},
+			FailedOptions: app.FailedOptions{
+				Attrs: map[string]string{"owner": "alice"},
+			},
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gotExitCode)
+		out := buf.String()
+		assert.Contains(t, out, "TestLogout/expired")
+		assert.NotContains(t, out, "TestNoOwner")
+		// The failed test belongs to bob.
+		assert.NotContains(t, out, "TestQuery")
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		inputFile := filepath.Join(base, "test_01.jsonl")
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName: inputFile,
+			Output:   buf,
+			Sorter:   parse.SortByPackageName,
+			TestTableOptions: app.TestTableOptions{
+				GroupAttr: "owner",
+			},
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gotExitCode)
+
+		goldenFile := filepath.Join(base, "test_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+	t.Run("table_incomplete", func(t *testing.T) {
+		t.Parallel()
+		// The output is cut short while TestQuery is running.
+		inputFile := filepath.Join(base, "test_03.jsonl")
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName:     inputFile,
+			Output:       buf,
+			DisableColor: true,
+			Sorter:       parse.SortByPackageName,
+			TestTableOptions: app.TestTableOptions{
+				GroupAttr: "owner",
+			},
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gotExitCode)
+
+		goldenFile := filepath.Join(base, "test_03.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+}
@@ -0,0 +1,119 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestBenchmarks(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "benchmark")
+
+	tt := []struct {
+		fileName string
+		exitCode int
+		// Expected benchmarks in the order they were reported.
+		want []parse.Benchmark
+		// Number of passed and failed tests, benchmarks included.
+		passed, failed int
+	}{
+		{
+			// go test -json -run=^$ -bench . -benchtime=1000x
+			"test_01", 0,
+			[]parse.Benchmark{
+				{Name: "BenchmarkJoin", Iterations: 1000, NsPerOp: 88.61, BytesPerOp: 8, AllocsPerOp: 1, HasMem: true},
+				{Name: "BenchmarkCustom", Iterations: 1000, NsPerOp: 118.3, Metrics: map[string]float64{"widgets/op": 42}},
+				{Name: "BenchmarkSub/small", Iterations: 1000, NsPerOp: 115.7},
+			},
+			4, 0,
+		},
+		{
+			// go test -json -run=^$ -bench 'Log|Fail' -cpu 1,4 -benchmem -benchtime=100x
+			"test_02", 1,
+			[]parse.Benchmark{
+				{Name: "BenchmarkLog", Iterations: 100, NsPerOp: 299.5, BytesPerOp: 11, HasMem: true},
+				{Name: "BenchmarkLog", Procs: 4, Iterations: 100, NsPerOp: 367.1, BytesPerOp: 15, HasMem: true},
+			},
+			1, 1,
+		},
+		{
+			// go test -json -run=^$ -bench 'Join|Custom' -benchmem -benchtime=100x -count=2
+			"test_03", 0,
+			[]parse.Benchmark{
+				{Name: "BenchmarkJoin", Iterations: 100, NsPerOp: 191.5, BytesPerOp: 8, AllocsPerOp: 1, HasMem: true},
+				{Name: "BenchmarkJoin", Iterations: 100, NsPerOp: 201.5, BytesPerOp: 8, AllocsPerOp: 1, HasMem: true},
+				{Name: "BenchmarkCustom", Iterations: 100, NsPerOp: 89.52, BytesPerOp: 16, AllocsPerOp: 1, HasMem: true, Metrics: map[string]float64{"widgets/op": 42}},
+				{Name: "BenchmarkCustom", Iterations: 100, NsPerOp: 84.89, BytesPerOp: 16, AllocsPerOp: 1, HasMem: true, Metrics: map[string]float64{"widgets/op": 42}},
+			},
+			2, 0,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.fileName, func(t *testing.T) {
+			f, err := os.Open(filepath.Join(base, tc.fileName+".jsonl"))
+			require.NoError(t, err)
+			defer f.Close()
+
+			summary, err := parse.Process(f)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exitCode, summary.ExitCode())
+
+			pkg, ok := summary.Packages["example.com/fx/bench"]
+			require.True(t, ok)
+			require.Len(t, pkg.Benchmarks, len(tc.want))
+			for i, want := range tc.want {
+				want.Package = "example.com/fx/bench"
+				assert.Equal(t, want, *pkg.Benchmarks[i])
+			}
+			assert.Len(t, pkg.TestsByAction(parse.ActionPass), tc.passed)
+			assert.Len(t, pkg.TestsByAction(parse.ActionFail), tc.failed)
+		})
+	}
+}
+
+func TestBenchmarksTable(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "benchmark")
+
+	// A failed benchmark has no result and gets a FAIL row instead, and each -count run gets a row
+	// of its own.
+	for _, tc := range []struct {
+		fileName string
+		exitCode int
+	}{
+		{"test_01", 0},
+		{"test_02", 1},
+		{"test_03", 0},
+	} {
+		t.Run(tc.fileName, func(t *testing.T) {
+			t.Parallel()
+
+			inputFile := filepath.Join(base, tc.fileName+".jsonl")
+			buf := bytes.NewBuffer(nil)
+			options := app.Options{
+				FileName:     inputFile,
+				Output:       buf,
+				DisableColor: true,
+				Sorter:       parse.SortByPackageName,
+			}
+			gotExitCode, err := app.Run(options)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exitCode, gotExitCode)
+
+			goldenFile := filepath.Join(base, tc.fileName+".golden")
+			want, err := os.ReadFile(goldenFile)
+			require.NoError(t, err)
+			checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+		})
+	}
+}
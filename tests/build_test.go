@@ -0,0 +1,120 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestBuildFailure(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		fileName string
+		// Package that failed to build.
+		pkg string
+		// ImportPath of the failed build linked to the package.
+		importPath string
+		errors     []string
+	}{
+		{
+			// go1.24 and above, build output reported as JSON.
+			"json",
+			filepath.Join("testdata", "build", "test_01.jsonl"),
+			"example.com/fx/broken",
+			"example.com/fx/broken [example.com/fx/broken.test]",
+			[]string{`broken/b.go:3:23: cannot use "x" (untyped string constant) as int value in return statement`},
+		},
+		{
+			// go vet failure reported as a build failure.
+			"json_vet",
+			filepath.Join("testdata", "build", "test_02.jsonl"),
+			"example.com/fx/example",
+			"example.com/fx/example [example.com/fx/example.test]",
+			[]string{
+				"example/example_test.go:5:1: ExampleGreet refers to unknown identifier: Greet",
+				"example/example_test.go:15:1: ExampleOK refers to unknown identifier: OK",
+			},
+		},
+		{
+			// A compiler error with a location longer than the summary table cuts the error to.
+			"json_long",
+			filepath.Join("testdata", "build", "test_03.jsonl"),
+			"example.com/fx/brokenlong/internal/configuration",
+			"example.com/fx/brokenlong/internal/configuration [example.com/fx/brokenlong/internal/configuration.test]",
+			[]string{`brokenlong/internal/configuration/environment_variables.go:4:9: cannot use "8080" (untyped string constant) as int value in return statement`},
+		},
+		{
+			// Before go1.24, build output printed as plain text.
+			"text",
+			filepath.Join("testdata", "follow-verbose", "test_06.jsonl"),
+			"github.com/marco-m/tparse-bugs",
+			"github.com/marco-m/tparse-bugs [github.com/marco-m/tparse-bugs.test]",
+			[]string{"./a_test.go:6:2: undefined: hello"},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := os.Open(tc.fileName)
+			require.NoError(t, err)
+			defer f.Close()
+
+			summary, err := parse.Process(f)
+			require.NoError(t, err)
+			assert.Equal(t, 2, summary.ExitCode())
+
+			pkg, ok := summary.Packages[tc.pkg]
+			require.True(t, ok)
+			assert.True(t, pkg.HasFailedBuildOrSetup)
+			assert.Equal(t, parse.ActionFail, pkg.Summary.Action)
+			assert.Equal(t, "build failed", pkg.Summary.Output)
+			require.NotNil(t, pkg.FailedBuild)
+			assert.True(t, pkg.FailedBuild.Failed)
+			assert.Equal(t, tc.importPath, pkg.FailedBuild.ImportPath)
+			assert.Equal(t, tc.errors, pkg.FailedBuild.Errors())
+
+			failures := summary.BuildFailures()
+			require.Len(t, failures, 1)
+			assert.Same(t, pkg.FailedBuild, failures[0])
+		})
+	}
+}
+
+func TestBuildFailureTable(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "build")
+
+	// The failed output has the full build output, the summary table the first compiler error with
+	// its location kept whole.
+	for _, name := range []string{"test_01", "test_02", "test_03"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			inputFile := filepath.Join(base, name+".jsonl")
+			buf := bytes.NewBuffer(nil)
+			options := app.Options{
+				FileName:     inputFile,
+				Output:       buf,
+				DisableColor: true,
+				Sorter:       parse.SortByPackageName,
+			}
+			gotExitCode, err := app.Run(options)
+			require.NoError(t, err)
+			assert.Equal(t, 2, gotExitCode)
+
+			goldenFile := filepath.Join(base, name+".golden")
+			want, err := os.ReadFile(goldenFile)
+			require.NoError(t, err)
+			checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package parsetest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/internal/utils"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestCompressedInput(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "failed")
+	input, err := os.ReadFile(filepath.Join(base, "test_01.jsonl"))
+	require.NoError(t, err)
+	want, err := os.ReadFile(filepath.Join(base, "test_01.golden"))
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	compressed := filepath.Join(dir, "test_01.jsonl.gz")
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err = zw.Write(input)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(compressed, buf.Bytes(), 0644))
+
+	followFile := filepath.Join(dir, "follow.out.gz")
+	follow, err := utils.CreateFile(followFile)
+	require.NoError(t, err)
+
+	// The output is the same as for the uncompressed input, see TestFailedTestsTable.
+	var output bytes.Buffer
+	exitCode, err := app.Run(app.Options{
+		FileName:           compressed,
+		Output:             &output,
+		Sorter:             parse.SortByPackageName,
+		FollowOutput:       true,
+		FollowOutputWriter: follow,
+		TestTableOptions: app.TestTableOptions{
+			Pass: true,
+			Skip: true,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, exitCode)
+	assert.Equal(t, string(want), output.String())
+
+	// The follow output is compressed, and complete once app.Run returns.
+	r, err := utils.OpenFile(followFile)
+	require.NoError(t, err)
+	defer r.Close()
+	summary, err := parse.Process(r)
+	require.NoError(t, err)
+	assert.NotEmpty(t, summary.Packages)
+}
@@ -0,0 +1,154 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestCoverProfile(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "coverprofile")
+	calc := "github.com/mfridman/tparse/tests/testdata/coverprofile/calc"
+
+	t.Run("totals", func(t *testing.T) {
+		t.Parallel()
+		profile := parseCoverProfile(t, filepath.Join(base, "test_01.out"))
+		assert.Equal(t, "set", profile.Mode)
+		// Sorted by name.
+		require.Len(t, profile.Files, 3)
+		assert.Equal(t, "example.com/fx/strutil/strutil.go", profile.Files[0].Name)
+		assert.Equal(t, calc+"/calc.go", profile.Files[1].Name)
+		assert.Equal(t, calc, profile.Files[1].Package())
+		assert.Equal(t, parse.Coverage{Statements: 14, Covered: 9}, profile.Files[1].Coverage())
+		assert.Equal(t, map[string]parse.Coverage{
+			calc:                     {Statements: 18, Covered: 9},
+			"example.com/fx/strutil": {Statements: 8, Covered: 4},
+		}, profile.Packages())
+		// The total counts the statements of all packages.
+		assert.Equal(t, parse.Coverage{Statements: 26, Covered: 13}, profile.Total())
+		assert.InDelta(t, 50.0, profile.Total().Percent(), 0.001)
+	})
+	t.Run("functions", func(t *testing.T) {
+		t.Parallel()
+		profile := parseCoverProfile(t, filepath.Join(base, "test_01.out"))
+		src, err := os.ReadFile(filepath.Join(base, "calc", "calc.go"))
+		require.NoError(t, err)
+		funcs, err := profile.Files[1].Functions(src)
+		require.NoError(t, err)
+		var got []string
+		for _, fn := range funcs {
+			got = append(got, fn.Name)
+		}
+		assert.Equal(t, []string{"Add", "Div", "Abs", "(*Stack).Push", "(*Stack).Pop", "Stack.Len"}, got)
+		assert.Equal(t, 38, funcs[4].Line)
+		assert.Equal(t, parse.Coverage{Statements: 5, Covered: 4}, funcs[4].Coverage)
+	})
+	t.Run("merge_blocks", func(t *testing.T) {
+		t.Parallel()
+		// The same block reported by separate runs, e.g., with -coverpkg.
+		for _, tc := range []struct {
+			mode  string
+			count string
+			want  int
+		}{
+			{"set", "1", 1},
+			{"count", "4", 5},
+		} {
+			profile, err := parse.ParseCoverProfile(strings.NewReader(
+				"mode: " + tc.mode + "\n" +
+					"example.com/foo/foo.go:3.2,4.10 2 0\n" +
+					"example.com/foo/foo.go:3.2,4.10 2 1\n" +
+					"mode: " + tc.mode + "\n" +
+					"example.com/foo/foo.go:3.2,4.10 2 " + tc.count + "\n",
+			))
+			require.NoError(t, err)
+			require.Len(t, profile.Files, 1)
+			require.Len(t, profile.Files[0].Blocks, 1)
+			assert.Equal(t, tc.want, profile.Files[0].Blocks[0].Count, tc.mode)
+			assert.Equal(t, parse.Coverage{Statements: 2, Covered: 2}, profile.Total())
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		for _, input := range []string{
+			"example.com/foo/foo.go:3.2,4.10 2 1\n",
+			"mode: set\nexample.com/foo/foo.go:3.2 2 1\n",
+			"mode: set\nmode: count\n",
+		} {
+			_, err := parse.ParseCoverProfile(strings.NewReader(input))
+			assert.Error(t, err, input)
+		}
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		// Functions are only listed for files in this module, i.e., not for example.com/fx/strutil.
+		inputFile := filepath.Join(base, "test_01.jsonl")
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName:     inputFile,
+			Output:       buf,
+			Sorter:       parse.SortByPackageName,
+			Format:       app.OutputFormatMarkdown,
+			CoverProfile: filepath.Join(base, "test_01.out"),
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 0, gotExitCode)
+
+		goldenFile := filepath.Join(base, "test_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		inputFile := filepath.Join(base, "test_01.jsonl")
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName:     inputFile,
+			Output:       buf,
+			Sorter:       parse.SortByPackageName,
+			Format:       app.OutputFormatJSON,
+			CoverProfile: filepath.Join(base, "test_01.out"),
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 0, gotExitCode)
+
+		goldenFile := filepath.Join(base, "test_01_json.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+
+		report, err := parse.ReadReport(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err)
+		require.NotNil(t, report.CoverageProfile)
+		assert.Equal(t, 26, report.CoverageProfile.Total.Statements)
+		assert.Equal(t, 13, report.CoverageProfile.Total.Covered)
+		require.Len(t, report.CoverageProfile.Files, 3)
+		// Functions are only listed for files in this module.
+		assert.Empty(t, report.CoverageProfile.Files[0].Functions)
+		assert.Len(t, report.CoverageProfile.Files[1].Functions, 6)
+	})
+}
+
+func parseCoverProfile(t *testing.T, name string) *parse.CoverProfile {
+	t.Helper()
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+
+	profile, err := parse.ParseCoverProfile(f)
+	require.NoError(t, err)
+	return profile
+}
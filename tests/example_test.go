@@ -0,0 +1,78 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestExampleFailure(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(filepath.Join("testdata", "example", "test_01.jsonl"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	summary, err := parse.Process(f)
+	require.NoError(t, err)
+	pkg, ok := summary.Packages["example.com/fx/example"]
+	require.True(t, ok)
+
+	t.Run("ordered", func(t *testing.T) {
+		test := pkg.GetTest("ExampleGreet")
+		require.NotNil(t, test)
+		require.NotNil(t, test.Example)
+		assert.Equal(t, parse.ExampleFailure{Got: "hello\nworld\n!", Want: "hello\nthere\n!"}, *test.Example)
+		assert.Equal(t, []parse.DiffLine{
+			{Op: parse.DiffEqual, Text: "hello"},
+			{Op: parse.DiffRemoved, Text: "there"},
+			{Op: parse.DiffAdded, Text: "world"},
+			{Op: parse.DiffEqual, Text: "!"},
+		}, test.Example.Diff())
+	})
+	t.Run("unordered", func(t *testing.T) {
+		test := pkg.GetTest("ExampleUnordered")
+		require.NotNil(t, test)
+		require.NotNil(t, test.Example)
+		assert.True(t, test.Example.Unordered)
+		assert.Equal(t, []parse.DiffLine{
+			{Op: parse.DiffEqual, Text: "a"},
+			{Op: parse.DiffRemoved, Text: "c"},
+			{Op: parse.DiffAdded, Text: "b"},
+		}, test.Example.Diff())
+	})
+	t.Run("passed", func(t *testing.T) {
+		test := pkg.GetTest("ExampleOK")
+		require.NotNil(t, test)
+		assert.Nil(t, test.Example)
+	})
+	t.Run("table", func(t *testing.T) {
+		// The got and want output is printed as a diff, the same for JSON and plain text input.
+		for _, name := range []string{"test_01.jsonl", "test_02.txt"} {
+			inputFile := filepath.Join("testdata", "example", name)
+			buf := bytes.NewBuffer(nil)
+			options := app.Options{
+				FileName:     inputFile,
+				Output:       buf,
+				DisableColor: true,
+				Sorter:       parse.SortByPackageName,
+			}
+			gotExitCode, err := app.Run(options)
+			require.NoError(t, err)
+			assert.Equal(t, 1, gotExitCode)
+
+			goldenFile := strings.TrimSuffix(inputFile, filepath.Ext(name)) + ".golden"
+			want, err := os.ReadFile(goldenFile)
+			require.NoError(t, err)
+			checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestFailures(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "failure")
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		pkg := processPackage(t, filepath.Join(base, "test_01.jsonl"), "example.com/fx/failure")
+
+		// Lines logged with t.Log are told apart from errors by the output type, since go1.25.
+		test := pkg.GetTest("TestLogAndError")
+		require.NotNil(t, test)
+		require.Len(t, test.Failures, 2)
+		assertFailure(t, test.Failures[0], "failure_test.go", 7, "got 1, want 2")
+		assertFailure(t, test.Failures[1], "failure_test.go", 8, "first line\nsecond line")
+		assert.Len(t, test.Failures[1].Events, 2)
+
+		test = pkg.GetTest("TestNested/outer/inner")
+		require.NotNil(t, test)
+		require.Len(t, test.Failures, 1)
+		assertFailure(t, test.Failures[0], "failure_test.go", 14, "deeply nested")
+
+		assert.Empty(t, pkg.GetTest("TestNested").Failures)
+		assert.Empty(t, pkg.GetTest("TestPass").Failures)
+	})
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+		pkg := processPackage(t, filepath.Join(base, "test_02.txt"), "example.com/fx/failure")
+
+		// Without an output type, lines logged by a failed test are reported as well.
+		test := pkg.GetTest("TestLogAndError")
+		require.NotNil(t, test)
+		require.Len(t, test.Failures, 3)
+		assertFailure(t, test.Failures[0], "failure_test.go", 6, "just a log line")
+		assertFailure(t, test.Failures[2], "failure_test.go", 8, "first line\nsecond line")
+
+		// The report of a nested subtest is indented by its depth.
+		test = pkg.GetTest("TestNested/outer/inner")
+		require.NotNil(t, test)
+		require.Len(t, test.Failures, 1)
+		assertFailure(t, test.Failures[0], "failure_test.go", 14, "deeply nested")
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		// Each failure is printed with its location on top. Lines logged with t.Log are printed
+		// as-is, unless the output type is unknown.
+		for _, name := range []string{"test_01.jsonl", "test_02.txt"} {
+			inputFile := filepath.Join(base, name)
+			buf := bytes.NewBuffer(nil)
+			options := app.Options{
+				FileName:     inputFile,
+				Output:       buf,
+				DisableColor: true,
+				Sorter:       parse.SortByPackageName,
+			}
+			gotExitCode, err := app.Run(options)
+			require.NoError(t, err)
+			assert.Equal(t, 1, gotExitCode)
+
+			goldenFile := strings.TrimSuffix(inputFile, filepath.Ext(name)) + ".golden"
+			want, err := os.ReadFile(goldenFile)
+			require.NoError(t, err)
+			checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+		}
+	})
+}
+
+func processPackage(t *testing.T, name, pkgName string) *parse.Package {
+	t.Helper()
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+
+	summary, err := parse.Process(f)
+	require.NoError(t, err)
+	pkg, ok := summary.Packages[pkgName]
+	require.True(t, ok)
+	return pkg
+}
+
+func assertFailure(t *testing.T, f *parse.Failure, file string, line int, message string) {
+	t.Helper()
+	assert.Equal(t, file, f.File)
+	assert.Equal(t, line, f.Line)
+	assert.Equal(t, message, f.Message)
+}
@@ -0,0 +1,103 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestFuzzFailure(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "fuzz")
+
+	tt := []struct {
+		fileName string
+		want     parse.FuzzFailure
+	}{
+		{
+			// go test -json -run=^$ -fuzz=FuzzReverse
+			"test_01", parse.FuzzFailure{
+				Target:     "FuzzReverse",
+				Package:    "example.com/fx/fuzz",
+				Phase:      parse.FuzzPhaseFuzzing,
+				Entry:      "81476e3145e0ed8c",
+				CorpusPath: "testdata/fuzz/FuzzReverse/81476e3145e0ed8c",
+			},
+		},
+		{
+			// go test -json, re-running the corpus entry written by test_01.
+			"test_02", parse.FuzzFailure{
+				Target:     "FuzzReverse",
+				Package:    "example.com/fx/fuzz",
+				Phase:      parse.FuzzPhaseSeed,
+				Entry:      "81476e3145e0ed8c",
+				CorpusPath: "testdata/fuzz/FuzzReverse/81476e3145e0ed8c",
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.fileName, func(t *testing.T) {
+			f, err := os.Open(filepath.Join(base, tc.fileName+".jsonl"))
+			require.NoError(t, err)
+			defer f.Close()
+
+			summary, err := parse.Process(f)
+			require.NoError(t, err)
+			assert.Equal(t, 1, summary.ExitCode())
+
+			pkg, ok := summary.Packages["example.com/fx/fuzz"]
+			require.True(t, ok)
+			test := pkg.GetTest("FuzzReverse")
+			require.NotNil(t, test)
+			require.NotNil(t, test.Fuzz)
+			assert.Equal(t, tc.want, *test.Fuzz)
+			assert.Equal(t, "go test -run=FuzzReverse/81476e3145e0ed8c example.com/fx/fuzz", test.Fuzz.RunCommand())
+
+			// Only the fuzz test itself carries the failing input.
+			for _, other := range pkg.Tests {
+				if other != test {
+					assert.Nil(t, other.Fuzz, other.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestFuzzFailureTable(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "fuzz")
+
+	// The failing input and the command to reproduce it replace the lines go test prints about
+	// them, and the progress of the fuzzing engine is left out.
+	for _, name := range []string{"test_01", "test_02"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			inputFile := filepath.Join(base, name+".jsonl")
+			buf := bytes.NewBuffer(nil)
+			options := app.Options{
+				FileName:     inputFile,
+				Output:       buf,
+				DisableColor: true,
+				Sorter:       parse.SortByPackageName,
+			}
+			gotExitCode, err := app.Run(options)
+			require.NoError(t, err)
+			assert.Equal(t, 1, gotExitCode)
+
+			goldenFile := filepath.Join(base, name+".golden")
+			want, err := os.ReadFile(goldenFile)
+			require.NoError(t, err)
+			checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestIncomplete(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "incomplete")
+
+	t.Run("killed", func(t *testing.T) {
+		t.Parallel()
+		// The test called os.Exit, so neither the test nor its parent reported a result.
+		pkg := processPackage(t, filepath.Join(base, "test_01.jsonl"), "example.com/fx/killed")
+		assert.Equal(t, parse.ActionFail, pkg.Summary.Action)
+		assert.Equal(t, parse.ActionPass, pkg.GetTest("TestBefore").Status())
+		assert.Equal(t, parse.ActionIncomplete, pkg.GetTest("TestExit").Status())
+		assert.Equal(t, parse.ActionIncomplete, pkg.GetTest("TestExit/child").Status())
+		assert.Empty(t, pkg.TestsByAction(parse.ActionFail))
+		assert.Len(t, pkg.TestsByAction(parse.ActionIncomplete), 2)
+	})
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+		f, err := os.Open(filepath.Join(base, "test_02.jsonl"))
+		require.NoError(t, err)
+		defer f.Close()
+		summary, err := parse.Process(f)
+		require.NoError(t, err)
+		// The package never finished, but has tests that never finished either.
+		assert.Equal(t, 1, summary.ExitCode())
+
+		pkg := summary.Packages["example.com/fx/tree"]
+		require.NotNil(t, pkg)
+		assert.Equal(t, "example.com/fx/tree", pkg.Summary.Package)
+		assert.Empty(t, pkg.Summary.Action)
+		for _, test := range pkg.Tests {
+			assert.Equal(t, parse.ActionIncomplete, test.Status(), test.Name)
+		}
+	})
+	t.Run("merge", func(t *testing.T) {
+		t.Parallel()
+		// A run that never finished takes precedence over a run that passed.
+		passed, err := parse.Process(strings.NewReader(`
+{"Action":"run","Package":"example.com/fx/killed","Test":"TestExit"}
+{"Action":"pass","Package":"example.com/fx/killed","Test":"TestExit","Elapsed":0}
+{"Action":"pass","Package":"example.com/fx/killed","Elapsed":0.001}
+`))
+		require.NoError(t, err)
+		require.Equal(t, 0, passed.ExitCode())
+		killed := processSummary(t, filepath.Join(base, "test_01.jsonl"))
+
+		merged := parse.Merge(passed, killed)
+		pkg := merged.Packages["example.com/fx/killed"]
+		require.NotNil(t, pkg)
+		assert.Equal(t, parse.ActionIncomplete, pkg.GetTest("TestExit").Status())
+		assert.Equal(t, 1, merged.ExitCode())
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		inputFile := filepath.Join(base, "test_01.jsonl")
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName: inputFile,
+			Output:   buf,
+			Sorter:   parse.SortByPackageName,
+			TestTableOptions: app.TestTableOptions{
+				Pass: true,
+				Skip: true,
+			},
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gotExitCode)
+
+		goldenFile := filepath.Join(base, "test_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+}
+
+func processSummary(t *testing.T, name string) *parse.GoTestSummary {
+	t.Helper()
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+
+	summary, err := parse.Process(f)
+	require.NoError(t, err)
+	return summary
+}
@@ -0,0 +1,134 @@
+package parsetest
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestLongLine(t *testing.T) {
+	t.Parallel()
+
+	// longLineInput returns go test output where TestNoisy in package example.com/noisy logs a
+	// single line of the given output, followed by a passing package.
+	longLineInput := func(t *testing.T, output string) string {
+		t.Helper()
+		// Same field order as cmd/test2json, where Output is last.
+		type event struct {
+			Action  parse.Action
+			Package string
+			Test    string  `json:",omitempty"`
+			Elapsed float64 `json:",omitempty"`
+			Output  string  `json:",omitempty"`
+		}
+		events := []event{
+			{Action: parse.ActionRun, Package: "example.com/noisy", Test: "TestNoisy"},
+			{Action: parse.ActionOutput, Package: "example.com/noisy", Test: "TestNoisy", Output: output},
+			{Action: parse.ActionFail, Package: "example.com/noisy", Test: "TestNoisy", Elapsed: 0.01},
+			{Action: parse.ActionFail, Package: "example.com/noisy", Elapsed: 0.02},
+			{Action: parse.ActionRun, Package: "example.com/quiet", Test: "TestQuiet"},
+			{Action: parse.ActionPass, Package: "example.com/quiet", Test: "TestQuiet", Elapsed: 0.01},
+			{Action: parse.ActionPass, Package: "example.com/quiet", Elapsed: 0.02},
+		}
+		var sb strings.Builder
+		for _, e := range events {
+			b, err := json.Marshal(e)
+			require.NoError(t, err)
+			sb.Write(b)
+			sb.WriteByte('\n')
+		}
+		return sb.String()
+	}
+	noisyOutput := func(t *testing.T, summary *parse.GoTestSummary) string {
+		t.Helper()
+		pkg := summary.Packages["example.com/noisy"]
+		require.NotNil(t, pkg)
+		test := pkg.GetTest("TestNoisy")
+		require.NotNil(t, test)
+		require.Len(t, test.Events, 3)
+		return test.Events[1].Output
+	}
+
+	t.Run("exceeds_scanner_buffer", func(t *testing.T) {
+		t.Parallel()
+		// Larger than the 64KiB default of bufio.Scanner, but within the default max line size.
+		output := "    noisy_test.go:10: " + strings.Repeat("x", 100_000) + "\n"
+		summary, err := parse.Process(strings.NewReader(longLineInput(t, output)))
+		require.NoError(t, err)
+		assert.Equal(t, output, noisyOutput(t, summary))
+		assert.Equal(t, parse.ActionFail, summary.Packages["example.com/noisy"].Summary.Action)
+		assert.Equal(t, parse.ActionPass, summary.Packages["example.com/quiet"].Summary.Action)
+		assert.Equal(t, 1, summary.ExitCode())
+	})
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+		output := "    noisy_test.go:10: " + strings.Repeat("x", 10_000) + "\n"
+		input := longLineInput(t, output)
+		summary, err := parse.Process(strings.NewReader(input), parse.WithMaxLineSize(4096))
+		require.NoError(t, err)
+		got := noisyOutput(t, summary)
+		assert.Less(t, len(got), 4096)
+		assert.True(t, strings.HasPrefix(got, "    noisy_test.go:10: xxx"))
+		assert.Regexp(t, `x\.\.\. \[truncated \d+ bytes\]\n$`, got)
+		// The results of the other tests and packages are unaffected.
+		assert.Equal(t, parse.ActionFail, summary.Packages["example.com/noisy"].GetTest("TestNoisy").Status())
+		assert.Equal(t, parse.ActionFail, summary.Packages["example.com/noisy"].Summary.Action)
+		assert.Equal(t, parse.ActionPass, summary.Packages["example.com/quiet"].Summary.Action)
+		assert.Equal(t, 1, summary.ExitCode())
+	})
+	t.Run("truncated_first_line", func(t *testing.T) {
+		t.Parallel()
+		// The long line is the first line of the input, before the input is known to be JSON.
+		output := "    noisy_test.go:10: " + strings.Repeat("x", 10_000) + "\n"
+		_, input, _ := strings.Cut(longLineInput(t, output), "\n")
+		summary, err := parse.Process(strings.NewReader(input), parse.WithMaxLineSize(4096))
+		require.NoError(t, err)
+		test := summary.Packages["example.com/noisy"].GetTest("TestNoisy")
+		require.NotNil(t, test)
+		require.Len(t, test.Events, 2)
+		assert.Regexp(t, `x\.\.\. \[truncated \d+ bytes\]\n$`, test.Events[0].Output)
+		assert.Equal(t, parse.ActionFail, summary.Packages["example.com/noisy"].Summary.Action)
+		assert.Equal(t, parse.ActionPass, summary.Packages["example.com/quiet"].Summary.Action)
+	})
+	t.Run("truncated_text", func(t *testing.T) {
+		t.Parallel()
+		// Plain text output, i.e., go test without -json, is truncated the same way.
+		input := "=== RUN   TestNoisy\n" +
+			"    noisy_test.go:10: " + strings.Repeat("x", 10_000) + "\n" +
+			"--- FAIL: TestNoisy (0.01s)\n" +
+			"FAIL\n" +
+			"FAIL\texample.com/noisy\t0.02s\n"
+		summary, err := parse.Process(strings.NewReader(input), parse.WithMaxLineSize(4096))
+		require.NoError(t, err)
+		test := summary.Packages["example.com/noisy"].GetTest("TestNoisy")
+		require.NotNil(t, test)
+		var got string
+		for _, e := range test.Events {
+			if strings.Contains(e.Output, "noisy_test.go:10") {
+				got = e.Output
+			}
+		}
+		assert.Less(t, len(got), 4096+64)
+		assert.Regexp(t, `x\.\.\. \[truncated \d+ bytes\]\n$`, got)
+		assert.Equal(t, parse.ActionFail, test.Status())
+	})
+	t.Run("truncated_escape_sequence", func(t *testing.T) {
+		t.Parallel()
+		// Cut the line at every offset within a run of escape sequences, the truncated output must
+		// always decode.
+		output := strings.Repeat("\t\"é\x01", 2000) + "\n"
+		input := longLineInput(t, output)
+		for size := 1000; size < 1020; size++ {
+			summary, err := parse.Process(strings.NewReader(input), parse.WithMaxLineSize(size))
+			require.NoError(t, err, "max line size %d", size)
+			got := noisyOutput(t, summary)
+			assert.Contains(t, got, "[truncated ", "max line size %d", size)
+			assert.Equal(t, parse.ActionPass, summary.Packages["example.com/quiet"].Summary.Action)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	// Shards of the same run, generated with:
+	//
+	//	go test -json -count=1 -run 'TestA|TestB' ./shard; go test -json ./shardb
+	//	go test -json -run 'TestC|TestD' ./shard; go test -json ./shardb
+	//	go test -json -run 'TestNone' ./shardb
+	process := func(t *testing.T, name string) *parse.GoTestSummary {
+		t.Helper()
+		f, err := os.Open(filepath.Join("testdata", "merge", name))
+		require.NoError(t, err)
+		defer f.Close()
+		summary, err := parse.Process(f)
+		require.NoError(t, err)
+		return summary
+	}
+
+	t.Run("shards", func(t *testing.T) {
+		t.Parallel()
+		shard1 := process(t, "shard_1.jsonl")
+		shard2 := process(t, "shard_2.jsonl")
+		shard3 := process(t, "shard_3.jsonl")
+		summary := parse.Merge(shard1, shard2, shard3)
+		require.Len(t, summary.Packages, 2)
+		assert.Equal(t, 1, summary.ExitCode())
+
+		// Tests of the same package run on different shards are combined.
+		pkg := summary.Packages["example.com/fx/shard"]
+		require.NotNil(t, pkg)
+		assert.Equal(t, parse.ActionFail, pkg.Summary.Action)
+		// Shards run in parallel, the package took as long as the slowest shard.
+		assert.InDelta(t, 0.004, pkg.Summary.Elapsed, 0.0001)
+		var names []string
+		for _, test := range pkg.Tests {
+			names = append(names, test.Name)
+		}
+		assert.Equal(t, []string{"TestA", "TestB", "TestB/sub", "TestC", "TestD"}, names)
+		assert.Len(t, pkg.TestsByAction(parse.ActionPass), 2)
+		assert.Len(t, pkg.TestsByAction(parse.ActionFail), 2)
+		assert.Len(t, pkg.TestsByAction(parse.ActionSkip), 1)
+		// Subtests are linked to their parent within the merged package.
+		sub := pkg.GetTest("TestB/sub")
+		require.NotNil(t, sub)
+		assert.Same(t, pkg.GetTest("TestB"), sub.Parent)
+
+		// A package that was fresh in one shard, cached in another and ran no tests in the last
+		// is neither cached nor reported as having no tests.
+		pkg = summary.Packages["example.com/fx/shardb"]
+		require.NotNil(t, pkg)
+		assert.Equal(t, parse.ActionPass, pkg.Summary.Action)
+		assert.False(t, pkg.Cached)
+		assert.False(t, pkg.NoTests)
+		require.Len(t, pkg.Tests, 1)
+		assert.Equal(t, "TestX", pkg.Tests[0].Name)
+		// Both runs of the test are kept.
+		assert.Len(t, pkg.Tests[0].Attempts(), 2)
+
+		// The inputs are not modified.
+		assert.True(t, shard2.Packages["example.com/fx/shardb"].Cached)
+		assert.Len(t, shard1.Packages["example.com/fx/shard"].Tests, 3)
+	})
+	t.Run("failure_takes_precedence", func(t *testing.T) {
+		t.Parallel()
+		// The same shard ran twice, e.g., a retried CI job.
+		failed := process(t, "shard_1.jsonl")
+		summary := parse.Merge(failed, failed)
+		pkg := summary.Packages["example.com/fx/shard"]
+		require.NotNil(t, pkg)
+		assert.Equal(t, parse.ActionFail, pkg.Summary.Action)
+		assert.Len(t, pkg.Tests, 3)
+		assert.Equal(t, parse.ActionFail, pkg.GetTest("TestB").Status())
+		assert.Len(t, pkg.GetTest("TestB").AttemptsByStatus(parse.ActionFail), 2)
+	})
+	t.Run("attempts", func(t *testing.T) {
+		t.Parallel()
+		run := func(action parse.Action) *parse.GoTestSummary {
+			input := `{"Action":"run","Package":"example.com/fx/flaky","Test":"TestFlaky"}
+{"Action":"` + string(action) + `","Package":"example.com/fx/flaky","Test":"TestFlaky","Elapsed":0.01}
+{"Action":"` + string(action) + `","Package":"example.com/fx/flaky","Elapsed":0.02}
+`
+			summary, err := parse.Process(strings.NewReader(input))
+			require.NoError(t, err)
+			return summary
+		}
+		// The test failed on the first shard and passed on the second, e.g., a retried CI job.
+		summary := parse.Merge(run(parse.ActionFail), run(parse.ActionPass))
+		test := summary.Packages["example.com/fx/flaky"].GetTest("TestFlaky")
+		require.NotNil(t, test)
+		assert.Equal(t, parse.ActionFail, test.Status())
+		assert.True(t, test.IsFlaky())
+		// The failed run takes precedence and comes last.
+		attempts := test.Attempts()
+		require.Len(t, attempts, 2)
+		assert.Equal(t, parse.ActionPass, attempts[0].Status)
+		assert.Equal(t, parse.ActionFail, attempts[1].Status)
+	})
+	t.Run("single", func(t *testing.T) {
+		t.Parallel()
+		shard := process(t, "shard_2.jsonl")
+		summary := parse.Merge(shard)
+		require.Len(t, summary.Packages, 2)
+		assert.True(t, summary.Packages["example.com/fx/shardb"].Cached)
+		assert.Equal(t, 0, summary.ExitCode())
+
+		// The package is a copy, even if there is nothing to merge.
+		in, out := shard.Packages["example.com/fx/shardb"], summary.Packages["example.com/fx/shardb"]
+		assert.NotSame(t, in, out)
+		require.Len(t, out.Tests, 1)
+		assert.NotSame(t, in.Tests[0], out.Tests[0])
+		assert.Same(t, out.Tests[0], out.GetTest("TestX"))
+		assert.Same(t, in.Tests[0], in.GetTest("TestX"))
+	})
+	t.Run("app", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		exitCode, err := app.Run(app.Options{
+			Output:       &buf,
+			DisableColor: true,
+			Sorter:       parse.SortByPackageName,
+			FileNames:    []string{filepath.Join("testdata", "merge", "shard_*.jsonl")},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, exitCode)
+		assert.Contains(t, buf.String(), "example.com/fx/shard")
+		assert.Contains(t, buf.String(), "example.com/fx/shardb")
+
+		_, err = app.Run(app.Options{
+			Output:    &buf,
+			FileNames: []string{filepath.Join("testdata", "merge", "missing_*.jsonl")},
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,61 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestPackageOutput(t *testing.T) {
+	t.Parallel()
+
+	inputFile := filepath.Join("testdata", "output", "test_01.jsonl")
+	want := "init: loading fixtures\n" +
+		"2026/10/17 20:30:44 leaked 2 goroutines after tests finished\n"
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		// TestMain failed the package after all tests passed.
+		pkg := processPackage(t, inputFile, "example.com/fx/testmain")
+		assert.Equal(t, parse.ActionFail, pkg.Summary.Action)
+		assert.Empty(t, pkg.TestsByAction(parse.ActionFail))
+		assert.Equal(t, want, outputOf(pkg.Output))
+	})
+	t.Run("untyped", func(t *testing.T) {
+		t.Parallel()
+		// Before go1.25 the lines that report the package result are not marked as framing.
+		data, err := os.ReadFile(inputFile)
+		require.NoError(t, err)
+		data = regexp.MustCompile(`,"OutputType":"\w+"`).ReplaceAll(data, nil)
+		summary, err := parse.Process(bytes.NewReader(data))
+		require.NoError(t, err)
+		pkg := summary.Packages["example.com/fx/testmain"]
+		require.NotNil(t, pkg)
+		assert.Equal(t, want, outputOf(pkg.Output))
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName: inputFile,
+			Output:   buf,
+			Sorter:   parse.SortByPackageName,
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gotExitCode)
+
+		goldenFile := filepath.Join("testdata", "output", "test_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+}
@@ -1,6 +1,7 @@
 package parsetest
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mfridman/tparse/internal/app"
 	"github.com/mfridman/tparse/parse"
 )
 
@@ -62,6 +64,11 @@ func TestPanic(t *testing.T) {
 				"github.com/mfridman/tparse/ignore": false,
 			},
 		},
+		{
+			"test_07.jsonl", expected{
+				"example.com/fx/panicker": true,
+			},
+		},
 	}
 
 	for _, tc := range tt {
@@ -91,3 +98,81 @@ func TestPanic(t *testing.T) {
 		})
 	}
 }
+
+func TestPanicTrace(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "panic")
+
+	t.Run("goroutines", func(t *testing.T) {
+		pkg := processPackage(t, filepath.Join(base, "test_07.jsonl"), "example.com/fx/panicker")
+		p := pkg.Panic()
+		require.NotNil(t, p)
+		assert.Equal(t, "TestLookup", p.Test)
+		assert.Equal(t, "runtime error: invalid memory address or nil pointer dereference", p.Message)
+		assert.Equal(t, "[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x5434ac]", p.Signal)
+
+		g := p.Goroutine
+		require.NotNil(t, g)
+		assert.Equal(t, []int{10}, g.IDs)
+		assert.Equal(t, "running", g.State)
+		require.Len(t, g.Frames, 6)
+		assert.Equal(t, "panic", g.Frames[2].Func)
+		assert.Equal(t, &parse.Frame{
+			Func: "example.com/fx/panicker.lookup",
+			File: "/tmp/fx/panicker/panicker_test.go",
+			Line: 8,
+		}, g.Frames[3])
+		assert.True(t, g.Frames[3].IsTest())
+		assert.False(t, g.Frames[5].IsTest())
+		require.NotNil(t, g.CreatedBy)
+		assert.Equal(t, "testing.(*T).Run", g.CreatedBy.Func)
+
+		// Goroutines 7, 8 and 9 have the same stack.
+		require.Len(t, p.Others, 3)
+		assert.Equal(t, []int{1}, p.Others[0].IDs)
+		assert.Nil(t, p.Others[0].CreatedBy)
+		assert.Equal(t, "main.main", p.Others[0].Frames[len(p.Others[0].Frames)-1].Func)
+		assert.Equal(t, []int{6}, p.Others[1].IDs)
+		assert.Equal(t, []int{7, 8, 9}, p.Others[2].IDs)
+		assert.Equal(t, "runnable", p.Others[2].State)
+	})
+	t.Run("recovered", func(t *testing.T) {
+		pkg := processPackage(t, filepath.Join(base, "test_03.jsonl"), "github.com/mfridman/tparse/tests")
+		p := pkg.Panic()
+		require.NotNil(t, p)
+		assert.Equal(t, "runtime error: invalid memory address or nil pointer dereference", p.Message)
+		require.NotNil(t, p.Goroutine)
+		require.Len(t, p.Goroutine.Frames, 7)
+		assert.Equal(t, "github.com/mfridman/tparse/tests_test.TestStatus.func1", p.Goroutine.Frames[2].Func)
+		assert.Empty(t, p.Others)
+	})
+	t.Run("no_panic", func(t *testing.T) {
+		pkg := processPackage(t, filepath.Join(base, "test_04.jsonl"), "github.com/mfridman/tparse/parse")
+		assert.Nil(t, pkg.Panic())
+	})
+	t.Run("table", func(t *testing.T) {
+		for _, raw := range []bool{false, true} {
+			buf := bytes.NewBuffer(nil)
+			inputFile := filepath.Join(base, "test_07.jsonl")
+			options := app.Options{
+				FileName:      inputFile,
+				DisableColor:  true,
+				Output:        buf,
+				Sorter:        parse.SortByPackageName,
+				FailedOptions: app.FailedOptions{RawPanic: raw},
+			}
+			gotExitCode, err := app.Run(options)
+			require.NoError(t, err)
+			assert.Equal(t, 1, gotExitCode)
+
+			goldenFile := filepath.Join(base, "test_07.golden")
+			if raw {
+				goldenFile = filepath.Join(base, "test_07_raw.golden")
+			}
+			want, err := os.ReadFile(goldenFile)
+			require.NoError(t, err)
+			checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+		}
+	})
+}
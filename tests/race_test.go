@@ -1,6 +1,7 @@
 package parsetest
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/mfridman/tparse/internal/app"
 	"github.com/mfridman/tparse/parse"
 )
 
@@ -53,6 +55,10 @@ func TestRaceDetected(t *testing.T) {
 		{
 			"test_08", expected{"github.com/mfridman/debug-go/testing": {"TestRace"}},
 		},
+		// The same race is triggered by TestFirst and TestSecond.
+		{
+			"test_09", expected{"example.com/fx/racer": {"TestFirst", "TestSecond", "TestMap"}},
+		},
 	}
 
 	for _, tc := range tt {
@@ -83,3 +89,92 @@ func TestRaceDetected(t *testing.T) {
 		})
 	}
 }
+
+func TestDataRaces(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "race")
+
+	t.Run("deduplicated", func(t *testing.T) {
+		t.Parallel()
+		pkg := processPackage(t, filepath.Join(base, "test_09.jsonl"), "example.com/fx/racer")
+		// TestFirst and TestSecond trigger the same race from different tests.
+		require.Len(t, pkg.DataRaces, 2)
+		assert.Equal(t, []string{"TestFirst", "TestSecond", "TestMap"}, pkg.DataRaceTests)
+
+		r := pkg.DataRaces[0]
+		assert.Equal(t, []string{"TestFirst", "TestSecond"}, r.Tests)
+		require.NotNil(t, r.Access)
+		assert.Equal(t, "Read", r.Access.Op)
+		assert.Equal(t, "0x00c0000182a8", r.Access.Address)
+		assert.Equal(t, 8, r.Access.Goroutine)
+		assert.Equal(t, []*parse.Frame{
+			{Func: "example.com/fx/racer.(*Counter).Inc", File: "/tmp/fx/racer/racer.go", Line: 8},
+			{Func: "example.com/fx/racer.IncTwice.func1", File: "/tmp/fx/racer/racer.go", Line: 16},
+		}, r.Access.Frames)
+		require.Len(t, r.Access.CreatedAt, 4)
+		assert.Equal(t, "example.com/fx/racer.IncTwice", r.Access.CreatedAt[0].Func)
+		require.NotNil(t, r.Previous)
+		assert.Equal(t, "Write", r.Previous.Op)
+		assert.Equal(t, 7, r.Previous.Goroutine)
+		require.Len(t, r.Previous.Frames, 5)
+		assert.True(t, r.Previous.Frames[2].IsTest())
+
+		assert.Equal(t, []string{"TestMap"}, pkg.DataRaces[1].Tests)
+
+		for _, name := range []string{"TestFirst", "TestSecond"} {
+			test := pkg.GetTest(name)
+			require.NotNil(t, test)
+			assert.Equal(t, []*parse.DataRace{r}, test.DataRaces)
+		}
+		assert.Empty(t, pkg.GetTest("TestClean").DataRaces)
+	})
+	t.Run("outside_test", func(t *testing.T) {
+		t.Parallel()
+		pkg := processPackage(t, filepath.Join(base, "test_06.jsonl"), "github.com/mfridman/debug-go/testing")
+		require.Len(t, pkg.DataRaces, 1)
+		r := pkg.DataRaces[0]
+		assert.Empty(t, r.Tests)
+		assert.Equal(t, 7, r.Access.Goroutine)
+		// The previous access was made by the main goroutine, which was not created by a go
+		// statement.
+		assert.Equal(t, 0, r.Previous.Goroutine)
+		assert.Empty(t, r.Previous.CreatedAt)
+	})
+	t.Run("merged", func(t *testing.T) {
+		t.Parallel()
+		var summaries []*parse.GoTestSummary
+		for range 2 {
+			f, err := os.Open(filepath.Join(base, "test_09.jsonl"))
+			require.NoError(t, err)
+			summary, err := parse.Process(f)
+			f.Close()
+			require.NoError(t, err)
+			summaries = append(summaries, summary)
+		}
+		pkg := parse.Merge(summaries...).Packages["example.com/fx/racer"]
+		require.NotNil(t, pkg)
+		require.Len(t, pkg.DataRaces, 2)
+		assert.Equal(t, []string{"TestFirst", "TestSecond"}, pkg.DataRaces[0].Tests)
+		assert.Len(t, pkg.GetTest("TestFirst").DataRaces, 1)
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		inputFile := filepath.Join(base, "test_09.jsonl")
+		options := app.Options{
+			FileName:     inputFile,
+			Output:       buf,
+			DisableColor: true,
+			Sorter:       parse.SortByPackageName,
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gotExitCode)
+
+		goldenFile := filepath.Join(base, "test_09.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+}
@@ -0,0 +1,132 @@
+package parsetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "report")
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		inputFile := filepath.Join("testdata", "failure", "test_01.jsonl")
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName: inputFile,
+			Output:   buf,
+			Sorter:   parse.SortByPackageName,
+			Format:   app.OutputFormatJSON,
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gotExitCode)
+
+		goldenFile := filepath.Join(base, "test_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+	t.Run("read", func(t *testing.T) {
+		t.Parallel()
+		f, err := os.Open(filepath.Join(base, "test_01.golden"))
+		require.NoError(t, err)
+		defer f.Close()
+		report, err := parse.ReadReport(f)
+		require.NoError(t, err)
+		assert.Equal(t, parse.ReportVersion, report.Version)
+		assert.Equal(t, 1, report.ExitCode)
+		assert.Equal(t, parse.ReportCounts{Pass: 1, Fail: 4}, report.Counts)
+		require.Len(t, report.Packages, 1)
+		pkg := report.Packages[0]
+		require.Len(t, pkg.Tests, 5)
+		assert.Equal(t, "TestLogAndError", pkg.Tests[0].Name)
+		assert.Equal(t, parse.ActionFail, pkg.Tests[0].Status)
+		require.Len(t, pkg.Tests[0].Failures, 2)
+		assert.Equal(t, "first line\nsecond line", pkg.Tests[0].Failures[1].Message)
+
+		summary := report.Summary()
+		require.Contains(t, summary.Packages, "example.com/fx/failure")
+		assert.Equal(t, parse.ActionFail, summary.Packages["example.com/fx/failure"].Summary.Action)
+		assert.Equal(t, 1, summary.ExitCode())
+	})
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		_, err := parse.ReadReport(strings.NewReader(`{"Time":"2024-01-01T00:00:00Z","Action":"start","Package":"foo"}`))
+		assert.ErrorIs(t, err, parse.ErrNotReport)
+		_, err = parse.ReadReport(strings.NewReader(`{"version": 2, "packages": []}`))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, parse.ErrNotReport)
+	})
+	t.Run("round trip", func(t *testing.T) {
+		t.Parallel()
+		for _, name := range []string{
+			filepath.Join("testdata", "panic", "timeout_01.jsonl"),
+			filepath.Join("testdata", "benchmark", "test_01.jsonl"),
+			filepath.Join("testdata", "race", "test_09.jsonl"),
+		} {
+			f, err := os.Open(name)
+			require.NoError(t, err)
+			defer f.Close()
+			want, err := parse.Process(f)
+			require.NoError(t, err)
+
+			data, err := json.Marshal(want.Report())
+			require.NoError(t, err)
+			report, err := parse.ReadReport(bytes.NewReader(data))
+			require.NoError(t, err)
+			got := report.Summary()
+			assert.Equal(t, want.ExitCode(), got.ExitCode(), name)
+			require.Len(t, got.Packages, len(want.Packages), name)
+			for pkgName, wantPkg := range want.Packages {
+				gotPkg := got.Packages[pkgName]
+				require.NotNil(t, gotPkg, pkgName)
+				if wantPkg.Timeout == nil {
+					assert.Nil(t, gotPkg.Timeout, pkgName)
+				} else {
+					require.NotNil(t, gotPkg.Timeout, pkgName)
+					assert.Equal(t, wantPkg.Timeout.After, gotPkg.Timeout.After, pkgName)
+					assert.Equal(t, wantPkg.Timeout.Running, gotPkg.Timeout.Running, pkgName)
+				}
+				assert.Equal(t, wantPkg.Benchmarks, gotPkg.Benchmarks, pkgName)
+				require.Len(t, gotPkg.DataRaces, len(wantPkg.DataRaces), pkgName)
+				for i, r := range wantPkg.DataRaces {
+					assert.Equal(t, r.Tests, gotPkg.DataRaces[i].Tests, pkgName)
+					assert.Equal(t, r.Access, gotPkg.DataRaces[i].Access, pkgName)
+					assert.Equal(t, r.Previous, gotPkg.DataRaces[i].Previous, pkgName)
+				}
+			}
+		}
+	})
+	t.Run("compare", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName:     filepath.Join("testdata", "cover", "test_01.jsonl"),
+			Output:       buf,
+			DisableColor: true,
+			Sorter:       parse.SortByPackageName,
+			Compare:      filepath.Join(base, "compare.json"),
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 0, gotExitCode)
+		out := buf.String()
+		assert.Contains(t, out, "86.7% (+6.5%)")
+		assert.Contains(t, out, "68.0% (-2.5%)")
+		// Not in the report.
+		assert.Contains(t, out, "60.8% (-)")
+	})
+}
@@ -0,0 +1,60 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestShuffle(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "shuffle")
+
+	// go test -shuffle=11 ./shuffle, with and without -json. TestRead fails because TestSet did not
+	// run before it.
+	for _, name := range []string{"test_01.jsonl", "test_02.txt"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			pkg := processPackage(t, filepath.Join(base, name), "example.com/fx/shuffle")
+			assert.True(t, pkg.Shuffled)
+			assert.Equal(t, int64(11), pkg.ShuffleSeed)
+			// TestSet ran after the failed test, so it is left out.
+			assert.Equal(t,
+				"go test -shuffle=11 -run='^(TestOther|TestRead)$' example.com/fx/shuffle",
+				pkg.ShuffleCommand(),
+			)
+		})
+	}
+	t.Run("not_shuffled", func(t *testing.T) {
+		t.Parallel()
+		pkg := processPackage(t, filepath.Join("testdata", "failure", "test_01.jsonl"), "example.com/fx/failure")
+		assert.False(t, pkg.Shuffled)
+		assert.Empty(t, pkg.ShuffleCommand())
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		inputFile := filepath.Join(base, "test_01.jsonl")
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName: inputFile,
+			Output:   buf,
+			Sorter:   parse.SortByPackageName,
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gotExitCode)
+
+		goldenFile := filepath.Join(base, "test_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+}
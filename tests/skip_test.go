@@ -0,0 +1,64 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestSkipReason(t *testing.T) {
+	t.Parallel()
+
+	inputFile := filepath.Join("testdata", "skip", "test_01.jsonl")
+
+	t.Run("reasons", func(t *testing.T) {
+		t.Parallel()
+		pkg := processPackage(t, inputFile, "example.com/fx/skips")
+		for name, want := range map[string]string{
+			"TestDocker": "needs docker",
+			// The last message logged before the test was skipped.
+			"TestPostgres":  "needs docker",
+			"TestSkipNow":   "",
+			"TestMultiline": "flaky on CI\nsee issue 42",
+			"TestRuns":      "",
+			// Skipped with t.SkipNow, the parent test was skipped with a reason.
+			"TestIntegration/users":  "integration tests disabled",
+			"TestIntegration/orders": "needs network",
+			"TestIntegration":        "integration tests disabled",
+		} {
+			test := pkg.GetTest(name)
+			require.NotNil(t, test, name)
+			assert.Equal(t, want, test.SkipReason(), name)
+		}
+		assert.Equal(t, parse.ActionPass, pkg.GetTest("TestRuns").Status())
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName: inputFile,
+			Output:   buf,
+			Sorter:   parse.SortByPackageName,
+			TestTableOptions: app.TestTableOptions{
+				Pass:       true,
+				Skip:       true,
+				GroupSkips: true,
+			},
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 0, gotExitCode)
+
+		goldenFile := filepath.Join("testdata", "skip", "test_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+}
@@ -0,0 +1,86 @@
+package parsetest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("completed", func(t *testing.T) {
+		// go test -count=1 fmt strings bytes bufio crypto log mime sort time -json
+		f, err := os.Open(filepath.Join("testdata", "metrics_test.jsonl"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		stream := parse.NewStream(f)
+		var events, tests int
+		var packages []string
+		for se, err := range stream.Events() {
+			require.NoError(t, err)
+			require.NotNil(t, se.Event)
+			events++
+			if se.Test != nil {
+				tests++
+				assert.Equal(t, se.Event.Test, se.Test.Name)
+				assert.Equal(t, se.Event.Action, se.Test.Status())
+			}
+			if se.Package != nil {
+				packages = append(packages, se.Package.Summary.Package)
+				// The live summary already reflects the completed package.
+				assert.Same(t, se.Package, stream.Summary().Packages[se.Event.Package])
+				assert.Equal(t, parse.ActionPass, se.Package.Summary.Action)
+			}
+		}
+		assert.Greater(t, events, 0)
+		assert.ElementsMatch(t, []string{"fmt", "strings", "bytes", "bufio", "crypto", "log", "mime", "sort", "time"}, packages)
+
+		var want int
+		for _, pkg := range stream.Summary().Packages {
+			want += len(pkg.Tests)
+		}
+		assert.Equal(t, want, tests)
+	})
+	t.Run("snapshot", func(t *testing.T) {
+		f, err := os.Open(filepath.Join("testdata", "metrics_test.jsonl"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		// Stop consuming after the first completed package.
+		stream := parse.NewStream(f)
+		for se, err := range stream.Events() {
+			require.NoError(t, err)
+			if se.Package != nil {
+				break
+			}
+		}
+		var completed int
+		for _, pkg := range stream.Summary().Packages {
+			if pkg.Summary.Action != "" {
+				completed++
+			}
+		}
+		assert.Equal(t, 1, completed)
+	})
+	t.Run("not_parsable", func(t *testing.T) {
+		f, err := os.Open(filepath.Join("testdata", "prescan", "test_04.txt"))
+		require.NoError(t, err)
+		defer f.Close()
+
+		var got error
+		for se, err := range parse.NewStream(f).Events() {
+			if err != nil {
+				assert.Nil(t, se)
+				got = err
+			}
+		}
+		require.ErrorIs(t, got, parse.ErrNotParsable)
+	})
+}
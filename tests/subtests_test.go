@@ -0,0 +1,111 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestSubtestTree(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open(filepath.Join("testdata", "subtests", "test_01.jsonl"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	summary, err := parse.Process(f)
+	require.NoError(t, err)
+	pkg, ok := summary.Packages["example.com/fx/tree"]
+	require.True(t, ok)
+
+	var roots []string
+	for _, test := range pkg.Root() {
+		roots = append(roots, test.Name)
+	}
+	assert.Equal(t, []string{"TestParent", "TestOwnFailure", "TestFlat"}, roots)
+
+	// Walk visits parents before their subtests, in the order they were first seen.
+	type node struct {
+		name  string
+		depth int
+	}
+	var walked []node
+	pkg.Walk(func(test *parse.Test) bool {
+		walked = append(walked, node{test.ShortName(), test.Depth()})
+		return true
+	})
+	assert.Equal(t, []node{
+		{"TestParent", 0},
+		{"pass", 1},
+		{"group", 1},
+		{"leaf_fail", 2},
+		{"leaf_pass", 2},
+		{"TestOwnFailure", 0},
+		{"sub", 1},
+		{"TestFlat", 0},
+	}, walked)
+
+	leaf := pkg.GetTest("TestParent/group/leaf_fail")
+	require.NotNil(t, leaf)
+	assert.True(t, leaf.IsSubtest())
+	assert.Equal(t, "TestParent/group", leaf.Parent.Name)
+	assert.Equal(t, "TestParent", leaf.Parent.Parent.Name)
+
+	// Failures that only bubbled up from a subtest are told apart from failures of their own.
+	propagated := map[string]bool{
+		"TestParent":                 true,
+		"TestParent/group":           true,
+		"TestParent/group/leaf_fail": false,
+		"TestOwnFailure":             false,
+	}
+	for _, test := range pkg.TestsByAction(parse.ActionFail) {
+		want, ok := propagated[test.Name]
+		require.True(t, ok, "unexpected failed test %q", test.Name)
+		assert.Equal(t, want, test.IsPropagatedFailure(), test.Name)
+	}
+
+	// Aggregate counts per top-level test.
+	var failed int
+	pkg.Root()[0].Walk(func(test *parse.Test) bool {
+		if test.Status() == parse.ActionFail && !test.IsPropagatedFailure() {
+			failed++
+		}
+		return true
+	})
+	assert.Equal(t, 1, failed)
+}
+
+func TestSubtestTreeTable(t *testing.T) {
+	t.Parallel()
+
+	// Subtests are listed under their parent in the tests table, and failed subtests are indented
+	// under their parent in the failed output, with failures that only bubbled up printed faint.
+	inputFile := filepath.Join("testdata", "subtests", "test_01.jsonl")
+	buf := bytes.NewBuffer(nil)
+	exitCode, err := app.Run(app.Options{
+		FileName: inputFile,
+		Output:   buf,
+		Sorter:   parse.SortByPackageName,
+		TestTableOptions: app.TestTableOptions{
+			Pass: true,
+			Skip: true,
+			Tree: true,
+		},
+		FailedOptions: app.FailedOptions{
+			Tree: true,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, exitCode)
+	goldenFile := filepath.Join("testdata", "subtests", "test_01.golden")
+	want, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+}
@@ -0,0 +1,50 @@
+// Package calc is a coverage fixture.
+package calc
+
+import "errors"
+
+// Add returns the sum of a and b.
+func Add(a, b int) int {
+	return a + b
+}
+
+// Div returns a divided by b.
+func Div(a, b int) (int, error) {
+	if b == 0 {
+		return 0, errors.New("division by zero")
+	}
+	return a / b, nil
+}
+
+// Abs returns the absolute value of n.
+func Abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Stack is a stack of ints.
+type Stack struct {
+	items []int
+}
+
+// Push adds n to the top of the stack.
+func (s *Stack) Push(n int) {
+	s.items = append(s.items, n)
+}
+
+// Pop removes and returns the top of the stack.
+func (s *Stack) Pop() (int, bool) {
+	if len(s.items) == 0 {
+		return 0, false
+	}
+	n := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return n, true
+}
+
+// Len returns the number of items on the stack.
+func (s Stack) Len() int {
+	return len(s.items)
+}
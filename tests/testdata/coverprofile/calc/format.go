@@ -0,0 +1,15 @@
+package calc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format returns the items of the stack, top first.
+func Format(s *Stack) string {
+	var parts []string
+	for i := len(s.items) - 1; i >= 0; i-- {
+		parts = append(parts, fmt.Sprint(s.items[i]))
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
@@ -0,0 +1,116 @@
+package parsetest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestTextOutput(t *testing.T) {
+	t.Parallel()
+
+	// Each plain text file has a JSON counterpart from the same go test invocation, run with
+	// -json. The plain text output must produce the same summary.
+	tt := []struct {
+		fileName string
+		// verbose is true for go test -v, which reports every test. Otherwise only failed tests
+		// are reported.
+		verbose bool
+	}{
+		// go test -v -count=1 -cover ./shard ./shardb ./tree ./notests
+		{"test_01", true},
+		// go test -count=1 ./shard ./shardb ./broken ./notests
+		{"test_02", false},
+		// go test -v -count=1 ./example
+		{"test_03", true},
+	}
+	process := func(t *testing.T, name string) *parse.GoTestSummary {
+		t.Helper()
+		f, err := os.Open(filepath.Join("testdata", "text", name))
+		require.NoError(t, err)
+		defer f.Close()
+		summary, err := parse.Process(f)
+		require.NoError(t, err)
+		return summary
+	}
+	for _, tc := range tt {
+		t.Run(tc.fileName, func(t *testing.T) {
+			t.Parallel()
+			got := process(t, tc.fileName+".txt")
+			want := process(t, tc.fileName+".jsonl")
+			assert.Equal(t, want.ExitCode(), got.ExitCode())
+			require.Len(t, got.Packages, len(want.Packages))
+			for name, wantPkg := range want.Packages {
+				gotPkg := got.Packages[name]
+				require.NotNil(t, gotPkg, name)
+				assert.Equal(t, wantPkg.Summary.Action, gotPkg.Summary.Action, name)
+				assert.Equal(t, wantPkg.HasFailedBuildOrSetup, gotPkg.HasFailedBuildOrSetup, name)
+				assert.Equal(t, wantPkg.NoTestFiles, gotPkg.NoTestFiles, name)
+				if wantPkg.FailedBuild != nil {
+					require.NotNil(t, gotPkg.FailedBuild, name)
+					assert.Equal(t, wantPkg.FailedBuild.Errors(), gotPkg.FailedBuild.Errors(), name)
+				}
+				tests := wantPkg.Tests
+				if tc.verbose {
+					assert.Len(t, gotPkg.Tests, len(tests), name)
+				} else {
+					tests = wantPkg.TestsByAction(parse.ActionFail)
+					assert.Len(t, gotPkg.TestsByAction(parse.ActionFail), len(tests), name)
+				}
+				for _, wantTest := range tests {
+					gotTest := gotPkg.GetTest(wantTest.Name)
+					require.NotNil(t, gotTest, wantTest.Name)
+					assert.Equal(t, wantTest.Status(), gotTest.Status(), wantTest.Name)
+					// The got and want output of a failed Example follows the result line.
+					assert.Equal(t, wantTest.Example, gotTest.Example, wantTest.Name)
+					if wantTest.Parent != nil {
+						require.NotNil(t, gotTest.Parent, wantTest.Name)
+						assert.Equal(t, wantTest.Parent.Name, gotTest.Parent.Name)
+					}
+					if tc.verbose {
+						// go test -json does not indent the result lines of subtests.
+						assert.Equal(t,
+							trimLines(outputOf(wantTest.Events)),
+							trimLines(outputOf(gotTest.Events)),
+							wantTest.Name,
+						)
+					}
+				}
+			}
+		})
+	}
+	t.Run("stream", func(t *testing.T) {
+		t.Parallel()
+		f, err := os.Open(filepath.Join("testdata", "text", "test_01.txt"))
+		require.NoError(t, err)
+		defer f.Close()
+		var packages []string
+		for se, err := range parse.NewStream(f).Events() {
+			require.NoError(t, err)
+			assert.NotEmpty(t, se.Event.Package)
+			if se.Package != nil {
+				packages = append(packages, se.Package.Summary.Package)
+			}
+		}
+		assert.Equal(t, []string{
+			"example.com/fx/shard",
+			"example.com/fx/shardb",
+			"example.com/fx/tree",
+		}, packages)
+	})
+}
+
+// trimLines removes leading whitespace from each line.
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimLeft(lines[i], " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package parsetest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestTimeline(t *testing.T) {
+	t.Parallel()
+
+	base := filepath.Join("testdata", "timeline")
+
+	t.Run("timing", func(t *testing.T) {
+		t.Parallel()
+		// Run with -parallel 2, so 2 of the 4 parallel subtests of TestParallel wait for a slot.
+		pkg := processPackage(t, filepath.Join(base, "test_01.jsonl"), "example.com/fx/parallel")
+		tests := make(map[string]*parse.Test)
+		for _, test := range pkg.Tests {
+			tests[test.Name] = test
+		}
+		require.Len(t, tests, 8)
+
+		serial := tests["TestSerial"]
+		assert.False(t, serial.IsParallel())
+		timing := serial.Timing()
+		require.Len(t, timing.Runs, 1)
+		assert.Zero(t, timing.Paused)
+		assert.Equal(t, timing.End.Sub(timing.Start), timing.Running)
+
+		var paused int
+		for _, name := range []string{"a", "b", "c", "d"} {
+			test := tests["TestParallel/"+name]
+			assert.True(t, test.IsParallel(), name)
+			timing := test.Timing()
+			assert.Equal(t, timing.End.Sub(timing.Start), timing.Running+timing.Paused, name)
+			if timing.Paused > timing.Running/2 {
+				paused++
+			}
+		}
+		assert.Equal(t, 2, paused)
+
+		assert.Equal(t, 2, pkg.PeakConcurrency())
+	})
+	t.Run("no_times", func(t *testing.T) {
+		t.Parallel()
+		test := &parse.Test{Events: []*parse.Event{
+			{Action: parse.ActionRun, Test: "TestFoo"},
+			{Action: parse.ActionPass, Test: "TestFoo"},
+		}}
+		assert.Equal(t, parse.Timing{}, test.Timing())
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		inputFile := filepath.Join(base, "test_01.jsonl")
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName: inputFile,
+			Output:   buf,
+			Sorter:   parse.SortByPackageName,
+			Timeline: true,
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 0, gotExitCode)
+
+		goldenFile := filepath.Join(base, "test_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+}
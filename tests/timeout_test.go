@@ -0,0 +1,90 @@
+package parsetest
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mfridman/tparse/internal/app"
+	"github.com/mfridman/tparse/parse"
+)
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+
+	inputFile := filepath.Join("testdata", "panic", "timeout_01.jsonl")
+
+	t.Run("running_tests", func(t *testing.T) {
+		t.Parallel()
+		f, err := os.Open(inputFile)
+		require.NoError(t, err)
+		defer f.Close()
+		summary, err := parse.Process(f)
+		require.NoError(t, err)
+		assert.Equal(t, 1, summary.ExitCode())
+
+		pkg := summary.Packages["example.com/fx/hang"]
+		require.NotNil(t, pkg)
+		assert.False(t, pkg.HasPanic)
+		assert.Nil(t, pkg.Panic())
+		require.NotNil(t, pkg.Timeout)
+		assert.Equal(t, time.Second, pkg.Timeout.After)
+		assert.Equal(t, []*parse.RunningTest{
+			{Name: "TestHang/cache", Elapsed: time.Second},
+			{Name: "TestHang/db", Elapsed: time.Second},
+		}, pkg.Timeout.Running)
+		assert.NotEmpty(t, pkg.Timeout.Events)
+		assert.Equal(t, parse.ActionFail, pkg.Summary.Action)
+		assert.InDelta(t, 1.007, pkg.Summary.Elapsed, 0.001)
+		assert.Equal(t, parse.ActionPass, pkg.GetTest("TestQuick").Status())
+	})
+	t.Run("running_tests_not_reported", func(t *testing.T) {
+		t.Parallel()
+		// Before go1.20 the running tests are not reported, they are derived from the tests that
+		// did not finish.
+		data, err := os.ReadFile(inputFile)
+		require.NoError(t, err)
+		var lines []string
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		for sc.Scan() {
+			if strings.Contains(sc.Text(), `"Output":"\t`) && !strings.Contains(sc.Text(), `"Output":"\t/`) {
+				continue
+			}
+			lines = append(lines, sc.Text())
+		}
+		summary, err := parse.Process(strings.NewReader(strings.Join(lines, "\n")))
+		require.NoError(t, err)
+		pkg := summary.Packages["example.com/fx/hang"]
+		require.NotNil(t, pkg)
+		require.NotNil(t, pkg.Timeout)
+		assert.Equal(t, []*parse.RunningTest{
+			{Name: "TestHang"},
+			{Name: "TestHang/db"},
+			{Name: "TestHang/cache"},
+		}, pkg.Timeout.Running)
+	})
+	t.Run("table", func(t *testing.T) {
+		t.Parallel()
+		buf := bytes.NewBuffer(nil)
+		options := app.Options{
+			FileName: inputFile,
+			Output:   buf,
+			Sorter:   parse.SortByPackageName,
+		}
+		gotExitCode, err := app.Run(options)
+		require.NoError(t, err)
+		assert.Equal(t, 1, gotExitCode)
+
+		goldenFile := filepath.Join("testdata", "panic", "timeout_01.golden")
+		want, err := os.ReadFile(goldenFile)
+		require.NoError(t, err)
+		checkGolden(t, inputFile, goldenFile, buf.Bytes(), want)
+	})
+}